@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+)
+
+// maxBatchSize is the maximum number of writes firestore accepts in a single batch
+const maxBatchSize = 500
+
+// batcher groups writes into batches, committing each one as soon as it is full
+type batcher struct {
+	DB      db.Env
+	batch   *firestore.WriteBatch
+	pending int
+}
+
+func newBatcher(DB db.Env) *batcher {
+	return &batcher{DB: DB, batch: DB.Client.Batch()}
+}
+
+func (b *batcher) set(ref *firestore.DocumentRef, data interface{}) error {
+	b.batch.Set(ref, data)
+	return b.added()
+}
+
+func (b *batcher) update(ref *firestore.DocumentRef, updates []firestore.Update) error {
+	b.batch.Update(ref, updates)
+	return b.added()
+}
+
+func (b *batcher) delete(ref *firestore.DocumentRef) error {
+	b.batch.Delete(ref)
+	return b.added()
+}
+
+func (b *batcher) added() error {
+	if b.pending++; b.pending == maxBatchSize {
+		return b.flush()
+	}
+
+	return nil
+}
+
+// flush commits the pending writes, if there are any
+func (b *batcher) flush() error {
+	if b.pending == 0 {
+		return nil
+	}
+
+	if _, err := b.batch.Commit(b.DB.Ctx); err != nil {
+		return fmt.Errorf("could not commit batch: %s", err.Error())
+	}
+
+	b.batch, b.pending = b.DB.Client.Batch(), 0
+	return nil
+}
@@ -0,0 +1,558 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Consistency checks, each one compares denormalized data with the documents it is derived from
+const (
+	CheckVotes  = "votes"  // comment votes (and their user_comments replicas) against users' comment ratings
+	CheckStats  = "stats"  // subject review stats against users' subject reviews
+	CheckGrades = "grades" // subject grades against users' records
+)
+
+// AllChecks lists every consistency check, in the order they run
+var AllChecks = []string{CheckVotes, CheckStats, CheckGrades}
+
+// Discrepancy is a denormalized value that does not match the documents it is derived from
+type Discrepancy struct {
+	Check    string
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("[%s] %s: expected %s, found %s", d.Check, d.Path, d.Expected, d.Actual)
+}
+
+// CheckConsistency runs the given checks and returns every discrepancy found
+//
+// If repair is set, denormalized values are recomputed from the source documents. Repairs overwrite sharded counters,
+// so votes or reviews made while it runs may be lost: it should be run when the backend is not serving requests.
+// If dryRun is set, repairs are only logged.
+func CheckConsistency(DB db.Env, checks []string, repair, dryRun bool) ([]Discrepancy, error) {
+	c := checker{DB: DB, repair: repair, dryRun: dryRun, batch: newBatcher(DB), subjects: make(map[string]*models.Subject)}
+
+	for _, check := range checks {
+		var err error
+		switch check {
+		case CheckVotes:
+			err = c.checkVotes()
+		case CheckStats:
+			err = c.checkStats()
+		case CheckGrades:
+			err = c.checkGrades()
+		default:
+			err = fmt.Errorf("unknown check %q", check)
+		}
+
+		if err != nil {
+			return c.found, err
+		}
+	}
+
+	if err := c.batch.flush(); err != nil {
+		return c.found, err
+	}
+
+	return c.found, nil
+}
+
+// checker holds the state shared by the consistency checks
+type checker struct {
+	DB     db.Env
+	repair bool
+	dryRun bool
+	batch  *batcher
+
+	found    []Discrepancy
+	subjects map[string]*models.Subject // subject hash -> subject
+}
+
+func (c *checker) report(d Discrepancy) {
+	log.Println(d)
+	c.found = append(c.found, d)
+}
+
+// writes reports whether repairs must be written
+func (c *checker) writes() bool {
+	return c.repair && !c.dryRun
+}
+
+func (c *checker) set(ref *firestore.DocumentRef, data interface{}) error {
+	log.Printf("repair: set %s\n", ref.Path)
+	if !c.writes() {
+		return nil
+	}
+
+	return c.batch.set(ref, data)
+}
+
+func (c *checker) update(ref *firestore.DocumentRef, updates []firestore.Update) error {
+	log.Printf("repair: update %s\n", ref.Path)
+	if !c.writes() {
+		return nil
+	}
+
+	return c.batch.update(ref, updates)
+}
+
+func (c *checker) delete(ref *firestore.DocumentRef) error {
+	if !c.writes() {
+		return nil
+	}
+
+	return c.batch.delete(ref)
+}
+
+// resetCounter deletes every shard of a counter, so that only the base value remains
+func (c *checker) resetCounter(counter db.ShardedCounter) error {
+	log.Printf("repair: reset counter %s in %s\n", counter.Key, counter.Collection.Path)
+	for _, ref := range counter.ShardRefs() {
+		if err := c.delete(ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subject fetches a subject by hash, caching it for later checks
+func (c *checker) subject(hash string) (*models.Subject, error) {
+	if sub, ok := c.subjects[hash]; ok {
+		return sub, nil
+	}
+
+	snap, err := c.DB.Client.Collection("subjects").Doc(hash).Get(c.DB.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get subject %s: %s", hash, err.Error())
+	}
+
+	var sub models.Subject
+	if err := snap.DataTo(&sub); err != nil {
+		return nil, fmt.Errorf("could not bind subject %s: %s", hash, err.Error())
+	}
+
+	c.subjects[hash] = &sub
+	return &sub, nil
+}
+
+// forEach iterates through every document of a query
+func (c *checker) forEach(query firestore.Query, f func(*firestore.DocumentSnapshot) error) error {
+	iter := query.Documents(c.DB.Ctx)
+	defer iter.Stop()
+
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := f(snap); err != nil {
+			return err
+		}
+	}
+}
+
+type votes struct {
+	up, down int
+}
+
+func (v votes) String() string {
+	return fmt.Sprintf("%d up/%d down", v.up, v.down)
+}
+
+// checkVotes compares the votes of each comment, and of its replica, with the users' comment ratings
+func (c *checker) checkVotes() error {
+	// comment key (subject hash/professor hash/comment id) -> votes
+	expected := make(map[string]votes)
+	if err := c.forEach(c.DB.Client.CollectionGroup("comment_ratings").Query, func(snap *firestore.DocumentSnapshot) error {
+		var rating models.CommentRating
+		if err := snap.DataTo(&rating); err != nil {
+			return fmt.Errorf("could not bind comment rating %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		subHash := models.Subject{Code: rating.Subject, CourseCode: rating.Course, Specialization: rating.Specialization}.Hash()
+		key := subHash + "/" + rating.ProfessorHash + "/" + rating.ID.String()
+
+		v := expected[key]
+		if rating.Upvote {
+			v.up++
+		} else {
+			v.down++
+		}
+		expected[key] = v
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not list comment ratings: %s", err.Error())
+	}
+
+	counters := make(map[string]map[string]map[string]interface{}) // offering path -> comment id -> counter values
+	return c.forEach(c.DB.Client.CollectionGroup("comments").Query, func(snap *firestore.DocumentSnapshot) error {
+		offRef := snap.Ref.Parent.Parent
+		if offRef == nil || offRef.Parent.Parent == nil {
+			return nil
+		}
+
+		subHash, profHash := offRef.Parent.Parent.ID, offRef.ID
+
+		var comment models.Comment
+		if err := snap.DataTo(&comment); err != nil {
+			return fmt.Errorf("could not bind comment %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		if _, ok := counters[offRef.Path]; !ok {
+			values, err := db.ReadCounters(c.DB.Ctx, models.VotesCollection(c.DB, subHash, profHash))
+			if err != nil {
+				return fmt.Errorf("could not read votes of %s: %s", offRef.Path, err.Error())
+			}
+
+			counters[offRef.Path] = values
+		}
+
+		want := expected[subHash+"/"+profHash+"/"+comment.ID.String()]
+
+		stored := comment
+		comment.AddVotes(counters[offRef.Path][snap.Ref.ID])
+		if got := (votes{comment.Upvotes, comment.Downvotes}); got != want {
+			c.report(Discrepancy{Check: CheckVotes, Path: snap.Ref.Path, Expected: want.String(), Actual: got.String()})
+
+			if c.repair {
+				if err := c.update(snap.Ref, []firestore.Update{
+					{Path: "upvotes", Value: want.up},
+					{Path: "downvotes", Value: want.down},
+				}); err != nil {
+					return err
+				}
+
+				if err := c.resetCounter(models.VotesCounter(c.DB, subHash, profHash, snap.Ref.ID)); err != nil {
+					return err
+				}
+			}
+		}
+
+		// the author's replica of the comment
+		sub, err := c.subject(subHash)
+		if err != nil {
+			return err
+		}
+
+		replicaRef := c.DB.Client.Doc(fmt.Sprintf("users/%s/user_comments/%s", snap.Ref.ID, models.UserComment{
+			ProfessorHash:  profHash,
+			Subject:        sub.Code,
+			Course:         sub.CourseCode,
+			Specialization: sub.Specialization,
+		}.Hash()))
+
+		replicaSnap, err := replicaRef.Get(c.DB.Ctx)
+		if status.Code(err) == codes.NotFound {
+			c.report(Discrepancy{Check: CheckVotes, Path: replicaRef.Path, Expected: "replica", Actual: "missing document"})
+			if c.repair {
+				stored.Upvotes, stored.Downvotes = want.up, want.down
+				return c.set(replicaRef, models.UserComment{
+					Comment:        stored,
+					ProfessorHash:  profHash,
+					Subject:        sub.Code,
+					Course:         sub.CourseCode,
+					Specialization: sub.Specialization,
+				})
+			}
+
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not get replica %s: %s", replicaRef.Path, err.Error())
+		}
+
+		var replica models.UserComment
+		if err := replicaSnap.DataTo(&replica); err != nil {
+			return fmt.Errorf("could not bind replica %s: %s", replicaRef.Path, err.Error())
+		}
+
+		if got := (votes{replica.Upvotes, replica.Downvotes}); got != want {
+			c.report(Discrepancy{Check: CheckVotes, Path: replicaRef.Path, Expected: want.String(), Actual: got.String()})
+			if c.repair {
+				return c.update(replicaRef, []firestore.Update{
+					{Path: "comment.upvotes", Value: want.up},
+					{Path: "comment.downvotes", Value: want.down},
+				})
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkStats compares the review stats of each subject with the users' subject reviews
+func (c *checker) checkStats() error {
+	expected := make(map[string]*models.SubjectStats) // subject hash -> stats
+	if err := c.forEach(c.DB.Client.CollectionGroup("subject_reviews").Query, func(snap *firestore.DocumentSnapshot) error {
+		var review models.SubjectReview
+		if err := snap.DataTo(&review); err != nil {
+			return fmt.Errorf("could not bind review %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		stats, ok := expected[snap.Ref.ID]
+		if !ok {
+			stats = &models.SubjectStats{}
+			expected[snap.Ref.ID] = stats
+		}
+
+		stats.AddReview(&review)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not list subject reviews: %s", err.Error())
+	}
+
+	return c.forEach(c.DB.Client.Collection("subjects").Query, func(snap *firestore.DocumentSnapshot) error {
+		var sub models.Subject
+		if err := snap.DataTo(&sub); err != nil {
+			return fmt.Errorf("could not bind subject %s: %s", snap.Ref.Path, err.Error())
+		}
+		sub.Stats.Normalize()
+
+		values, err := models.StatsCounter(c.DB, snap.Ref.ID).Read(c.DB.Ctx)
+		if err != nil {
+			return fmt.Errorf("could not read stats of %s: %s", snap.Ref.Path, err.Error())
+		}
+		sub.Stats.AddCounter(values)
+
+		want := models.NewSubjectStats()
+		if stats, ok := expected[snap.Ref.ID]; ok {
+			want.Total = stats.Total
+			for name, s := range stats.Categories {
+				want.Categories[name] = s
+			}
+		}
+
+		if sub.Stats.Equal(want) {
+			return nil
+		}
+
+		c.report(Discrepancy{
+			Check:    CheckStats,
+			Path:     snap.Ref.Path,
+			Expected: fmt.Sprintf("%d reviews", want.Total),
+			Actual:   fmt.Sprintf("%d reviews (or different answers)", sub.Stats.Total),
+		})
+
+		if !c.repair {
+			return nil
+		}
+
+		if err := c.update(snap.Ref, []firestore.Update{{Path: "stats", Value: want}}); err != nil {
+			return err
+		}
+
+		return c.resetCounter(models.StatsCounter(c.DB, snap.Ref.ID))
+	})
+}
+
+// gradeKey groups the grades created from records of the same year, semester and value
+type gradeKey struct {
+	Year     int
+	Semester int
+	Value    float64
+}
+
+func (k gradeKey) String() string {
+	value := strconv.FormatFloat(k.Value, 'f', -1, 64)
+	if k.Year == 0 {
+		return value
+	}
+
+	return fmt.Sprintf("%s in %d/%d", value, k.Year, k.Semester)
+}
+
+func (k gradeKey) less(other gradeKey) bool {
+	if k.Year != other.Year {
+		return k.Year < other.Year
+	}
+
+	if k.Semester != other.Semester {
+		return k.Semester < other.Semester
+	}
+
+	return k.Value < other.Value
+}
+
+// storedGrade is a subject grade along with its document
+type storedGrade struct {
+	ref *firestore.DocumentRef
+	models.Grade
+}
+
+// gradeDiff is a group of grades whose count does not match the records they are created from
+type gradeDiff struct {
+	key       gradeKey
+	want, got int
+
+	extra   []*firestore.DocumentRef // grades left without a record
+	missing []models.Record          // records left without a grade
+}
+
+// diffGrades matches the grades of a subject with the users' records of it
+//
+// Grades are matched by year, semester and value. Older grades only have a value, so they are matched to the records
+// of that value that are left without a grade; the ones that remain unmatched have a key with no year
+func diffGrades(records []models.Record, grades []storedGrade) []gradeDiff {
+	want := make(map[gradeKey][]models.Record)
+	for _, rec := range records {
+		key := gradeKey{Year: rec.Year, Semester: rec.Semester, Value: rec.Grade}
+		want[key] = append(want[key], rec)
+	}
+
+	have := make(map[gradeKey][]*firestore.DocumentRef)
+	legacy := make(map[float64][]*firestore.DocumentRef)
+	for _, g := range grades {
+		if g.Year == 0 {
+			legacy[g.Value] = append(legacy[g.Value], g.ref)
+			continue
+		}
+
+		key := gradeKey{Year: g.Year, Semester: g.Semester, Value: g.Value}
+		have[key] = append(have[key], g.ref)
+	}
+
+	keys := make([]gradeKey, 0, len(want)+len(have))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range have {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	diffs := make([]gradeDiff, 0)
+	for _, k := range keys {
+		recs, refs := want[k], have[k]
+		diff := gradeDiff{key: k, want: len(recs), got: len(refs)}
+
+		if len(refs) > len(recs) {
+			diff.extra = refs[len(recs):]
+		} else if len(recs) > len(refs) {
+			diff.missing = recs[len(refs):]
+
+			// older grades of the same value stand for the missing ones
+			claimed := len(legacy[k.Value])
+			if claimed > len(diff.missing) {
+				claimed = len(diff.missing)
+			}
+
+			legacy[k.Value] = legacy[k.Value][claimed:]
+			diff.missing = diff.missing[claimed:]
+			diff.got += claimed
+		}
+
+		if diff.want != diff.got {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	values := make([]float64, 0, len(legacy))
+	for v, refs := range legacy {
+		if len(refs) > 0 {
+			values = append(values, v)
+		}
+	}
+	sort.Float64s(values)
+
+	for _, v := range values {
+		diffs = append(diffs, gradeDiff{key: gradeKey{Value: v}, got: len(legacy[v]), extra: legacy[v]})
+	}
+
+	return diffs
+}
+
+// checkGrades compares the anonymous grades of each subject with the users' records
+//
+// Missing grades are created from their records, with the offering found the same way as when the user signed up
+func (c *checker) checkGrades() error {
+	expected := make(map[string][]models.Record) // subject hash -> records
+	if err := c.forEach(c.DB.Client.CollectionGroup("records").Query, func(snap *firestore.DocumentSnapshot) error {
+		// records are stored in users/{user}/final_scores/{subject}/records
+		scoreRef := snap.Ref.Parent.Parent
+		if scoreRef == nil || scoreRef.Parent.ID != "final_scores" {
+			return nil
+		}
+
+		var rec models.Record
+		if err := snap.DataTo(&rec); err != nil {
+			return fmt.Errorf("could not bind record %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		expected[scoreRef.ID] = append(expected[scoreRef.ID], rec)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not list records: %s", err.Error())
+	}
+
+	return c.forEach(c.DB.Client.Collection("subjects").Query, func(snap *firestore.DocumentSnapshot) error {
+		snaps, err := snap.Ref.Collection("grades").Documents(c.DB.Ctx).GetAll()
+		if err != nil {
+			return fmt.Errorf("could not list grades of %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		grades := make([]storedGrade, 0, len(snaps))
+		for _, g := range snaps {
+			grade := storedGrade{ref: g.Ref}
+			if err := g.DataTo(&grade.Grade); err != nil {
+				return fmt.Errorf("could not bind grade %s: %s", g.Ref.Path, err.Error())
+			}
+
+			grades = append(grades, grade)
+		}
+
+		var offerings map[string]*models.Offering
+		for _, diff := range diffGrades(expected[snap.Ref.ID], grades) {
+			c.report(Discrepancy{
+				Check:    CheckGrades,
+				Path:     snap.Ref.Path + "/grades",
+				Expected: fmt.Sprintf("%d grades %s", diff.want, diff.key),
+				Actual:   fmt.Sprintf("%d grades %s", diff.got, diff.key),
+			})
+
+			if !c.repair {
+				continue
+			}
+
+			// remove extra grades
+			for _, ref := range diff.extra {
+				log.Printf("repair: delete %s\n", ref.Path)
+				if err := c.delete(ref); err != nil {
+					return err
+				}
+			}
+
+			if len(diff.missing) > 0 && offerings == nil {
+				if offerings, err = db.ListByID[models.Offering](c.DB, "subjects/"+snap.Ref.ID+"/offerings", db.Query{}); err != nil {
+					return fmt.Errorf("could not list offerings of %s: %s", snap.Ref.Path, err.Error())
+				}
+			}
+
+			// add missing grades
+			for _, rec := range diff.missing {
+				if err := c.set(snap.Ref.Collection("grades").NewDoc(), models.NewGradeFromRecord(rec, offerings)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffGrades(t *testing.T) {
+	grade := func(id string, value float64, year, semester int) storedGrade {
+		return storedGrade{ref: &firestore.DocumentRef{ID: id}, Grade: models.Grade{Value: value, Year: year, Semester: semester}}
+	}
+
+	records := []models.Record{
+		{Grade: 7, Year: 2020, Semester: 1},
+		{Grade: 7, Year: 2021, Semester: 1},
+		{Grade: 5, Year: 2021, Semester: 2},
+		{Grade: 3, Year: 2019, Semester: 1},
+	}
+
+	grades := []storedGrade{
+		grade("a", 7, 2020, 1), // matches its record
+		grade("b", 7, 2020, 1), // same value and year as another grade, but only one record
+		grade("c", 5, 0, 0),    // older grade, stands for the 2021 record of the same value
+		grade("d", 9, 0, 0),    // older grade without a record
+	}
+
+	diffs := diffGrades(records, grades)
+	require.Len(t, diffs, 4)
+
+	// the record of 2019 has no grade, and is recreated with its year and semester
+	assert.Equal(t, gradeKey{Year: 2019, Semester: 1, Value: 3}, diffs[0].key)
+	assert.Equal(t, []models.Record{records[3]}, diffs[0].missing)
+
+	// grades of the same value in other years are not deleted in its place
+	assert.Equal(t, gradeKey{Year: 2020, Semester: 1, Value: 7}, diffs[1].key)
+	require.Len(t, diffs[1].extra, 1)
+	assert.Equal(t, "b", diffs[1].extra[0].ID)
+
+	assert.Equal(t, gradeKey{Year: 2021, Semester: 1, Value: 7}, diffs[2].key)
+	assert.Equal(t, []models.Record{records[1]}, diffs[2].missing)
+
+	assert.Equal(t, gradeKey{Value: 9}, diffs[3].key)
+	require.Len(t, diffs[3].extra, 1)
+	assert.Equal(t, "d", diffs[3].extra[0].ID)
+}
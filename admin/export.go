@@ -0,0 +1,266 @@
+package admin
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"google.golang.org/api/iterator"
+)
+
+// usersCollection is the root collection that holds every user and their private subcollections
+const usersCollection = "users"
+
+// transcriptCollection is the subcollection of a user that holds their final scores and records
+const transcriptCollection = "final_scores"
+
+// ExportOptions controls which documents are exported and how
+type ExportOptions struct {
+	// Collections restricts the export to these root collections, every root collection is exported if empty
+	Collections []string
+
+	// SkipUsers leaves the users collection out. Comments and votes are still keyed by user hashes,
+	// which are only replaced if AnonymizeUsers is also set
+	SkipUsers bool
+
+	// AnonymizeUsers replaces every user hash with a random pseudonym, wherever it is used as a
+	// document ID or as a value, and clears the name, email and password of each user.
+	//
+	// Users' transcripts (final_scores and their records) are left out: the subjects, years and grades of a student are
+	// enough to identify them, pseudonym or not. Anonymous subject grades are still exported
+	AnonymizeUsers bool
+}
+
+// exporter walks the collection tree, writing one record per document
+type exporter struct {
+	DB   db.Env
+	opts ExportOptions
+	out  *json.Encoder
+
+	pseudonyms map[string]string // user hash -> pseudonym, only used when anonymizing
+	exported   int
+}
+
+// Export writes every document of the database to w as NDJSON, one record per line
+//
+// Documents that do not exist but have subcollections (such as users' final_scores) are not written,
+// but their subcollections are. It returns the number of documents written
+func Export(DB db.Env, w io.Writer, opts ExportOptions) (int, error) {
+	buf := bufio.NewWriter(w)
+	e := exporter{DB: DB, opts: opts, out: json.NewEncoder(buf)}
+
+	// users are listed even if they are skipped, their hashes are also in comments, votes and professor data
+	if opts.AnonymizeUsers {
+		if err := e.collectPseudonyms(); err != nil {
+			return 0, err
+		}
+	}
+
+	roots, err := DB.Client.Collections(DB.Ctx).GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("could not list root collections: %s", err.Error())
+	}
+
+	for _, col := range roots {
+		if !e.exportsRoot(col.ID) {
+			continue
+		}
+
+		if err := e.walkCollection(col); err != nil {
+			return e.exported, err
+		}
+	}
+
+	if err := buf.Flush(); err != nil {
+		return e.exported, fmt.Errorf("could not write export: %s", err.Error())
+	}
+
+	return e.exported, nil
+}
+
+func (e *exporter) exportsRoot(id string) bool {
+	if id == usersCollection && e.opts.SkipUsers {
+		return false
+	}
+
+	if len(e.opts.Collections) == 0 {
+		// cache entries are derived from the other collections and would be stale once imported
+		return id != db.CacheCollection
+	}
+
+	for _, c := range e.opts.Collections {
+		if c == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exportsSubcollection reports whether a subcollection is exported, users' transcripts are left out of anonymized exports
+func (e *exporter) exportsSubcollection(col *firestore.CollectionRef) bool {
+	if !e.opts.AnonymizeUsers || col.ID != transcriptCollection || col.Parent == nil {
+		return true
+	}
+
+	return col.Parent.Parent == nil || col.Parent.Parent.ID != usersCollection
+}
+
+// collectPseudonyms assigns a pseudonym to every user
+//
+// Pseudonyms are hashed with a random salt, so they cannot be traced back to the original hashes
+func (e *exporter) collectPseudonyms() error {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("could not generate salt: %s", err.Error())
+	}
+
+	refs, err := e.DB.Client.Collection(usersCollection).DocumentRefs(e.DB.Ctx).GetAll()
+	if err != nil {
+		return fmt.Errorf("could not list users: %s", err.Error())
+	}
+
+	e.pseudonyms = make(map[string]string, len(refs))
+	for _, ref := range refs {
+		e.pseudonyms[ref.ID] = utils.SHA256(hex.EncodeToString(salt) + ref.ID)
+	}
+
+	return nil
+}
+
+// walkCollection exports the documents of a collection and, recursively, their subcollections
+func (e *exporter) walkCollection(col *firestore.CollectionRef) error {
+	iter := col.DocumentRefs(e.DB.Ctx)
+
+	chunk := make([]*firestore.DocumentRef, 0, maxBatchSize)
+	for {
+		ref, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return fmt.Errorf("could not list %s: %s", col.Path, err.Error())
+		}
+
+		if chunk = append(chunk, ref); len(chunk) == maxBatchSize {
+			if err := e.exportDocuments(chunk); err != nil {
+				return err
+			}
+
+			chunk = chunk[:0]
+		}
+	}
+
+	return e.exportDocuments(chunk)
+}
+
+func (e *exporter) exportDocuments(refs []*firestore.DocumentRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	snaps, err := e.DB.Client.GetAll(e.DB.Ctx, refs)
+	if err != nil {
+		return fmt.Errorf("could not get documents of %s: %s", refs[0].Parent.Path, err.Error())
+	}
+
+	for _, snap := range snaps {
+		if snap.Exists() {
+			if err := e.write(snap); err != nil {
+				return err
+			}
+		}
+
+		subcollections, err := snap.Ref.Collections(e.DB.Ctx).GetAll()
+		if err != nil {
+			return fmt.Errorf("could not list subcollections of %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		for _, col := range subcollections {
+			if !e.exportsSubcollection(col) {
+				continue
+			}
+
+			if err := e.walkCollection(col); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) write(snap *firestore.DocumentSnapshot) error {
+	path := relativePath(snap.Ref)
+
+	data, err := encodeData(snap.Data())
+	if err != nil {
+		return fmt.Errorf("could not encode %s: %s", path, err.Error())
+	}
+
+	if e.pseudonyms != nil {
+		path, data = e.anonymize(path, data)
+	}
+
+	if err := e.out.Encode(record{Path: path, Data: data}); err != nil {
+		return fmt.Errorf("could not write %s: %s", path, err.Error())
+	}
+
+	if e.exported++; e.exported%1000 == 0 {
+		log.Printf("exported %d documents\n", e.exported)
+	}
+
+	return nil
+}
+
+// anonymize replaces user hashes in the document path and data with their pseudonyms
+func (e *exporter) anonymize(path string, data map[string]interface{}) (string, map[string]interface{}) {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = e.pseudonymize(s)
+	}
+
+	if len(segments) == 2 && segments[0] == usersCollection {
+		data["name"], data["email"], data["password"] = "", "", ""
+	}
+
+	return strings.Join(segments, "/"), e.anonymizeValue(data).(map[string]interface{})
+}
+
+// pseudonymize replaces a user hash, which may be followed by a suffix such as a counter shard number
+func (e *exporter) pseudonymize(s string) string {
+	hash, suffix := s, ""
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		hash, suffix = s[:i], s[i:]
+	}
+
+	if p, ok := e.pseudonyms[hash]; ok {
+		return p + suffix
+	}
+
+	return s
+}
+
+func (e *exporter) anonymizeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return e.pseudonymize(value)
+	case []interface{}:
+		for i := range value {
+			value[i] = e.anonymizeValue(value[i])
+		}
+	case map[string]interface{}:
+		for k := range value {
+			value[k] = e.anonymizeValue(value[k])
+		}
+	}
+
+	return v
+}
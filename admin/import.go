@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+)
+
+// ImportOptions controls which records of an export are imported
+type ImportOptions struct {
+	// SkipUsers leaves out the users collection and its subcollections
+	SkipUsers bool
+
+	// DryRun only parses the export, nothing is written
+	DryRun bool
+}
+
+// document is the raw data of an imported document
+//
+// It only implements db.Writer to be written with db.Env.BatchWrite, which knows its ID
+type document map[string]interface{}
+
+// errNoPath is returned by the single document writes, which would write to a random ID instead of the document's path
+var errNoPath = errors.New("imported documents can only be written with BatchWrite")
+
+func (d document) Insert(DB db.Env, collection string) error {
+	return errNoPath
+}
+
+func (d document) Update(DB db.Env, collection string) error {
+	return errNoPath
+}
+
+// Import reads an export written by Export and writes every document to the database
+//
+// Documents are overwritten, documents that are not in the export are left untouched.
+// It returns the number of documents imported
+func Import(DB db.Env, r io.Reader, opts ImportOptions) (int, error) {
+	reader := bufio.NewReader(r)
+	objs := make([]db.Object, 0, maxBatchSize)
+	imported := 0
+
+	flush := func() error {
+		if len(objs) == 0 {
+			return nil
+		}
+
+		if !opts.DryRun {
+			if err := DB.BatchWrite(objs); err != nil {
+				return fmt.Errorf("could not write batch: %s", err.Error())
+			}
+		}
+
+		imported += len(objs)
+		log.Printf("imported %d documents\n", imported)
+
+		objs = objs[:0]
+		return nil
+	}
+
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return imported, fmt.Errorf("could not read line %d: %s", line, err.Error())
+		}
+
+		if len(bytes.TrimSpace(raw)) > 0 {
+			obj, skip, parseErr := parseRecord(DB, raw, opts)
+			if parseErr != nil {
+				return imported, fmt.Errorf("line %d: %s", line, parseErr.Error())
+			}
+
+			if !skip {
+				if objs = append(objs, obj); len(objs) == maxBatchSize {
+					if err := flush(); err != nil {
+						return imported, err
+					}
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return imported, flush()
+}
+
+func parseRecord(DB db.Env, raw []byte, opts ImportOptions) (obj db.Object, skip bool, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var rec record
+	if err := decoder.Decode(&rec); err != nil {
+		return obj, false, fmt.Errorf("invalid record: %s", err.Error())
+	}
+
+	if opts.SkipUsers && strings.HasPrefix(rec.Path, usersCollection+"/") {
+		return obj, true, nil
+	}
+
+	// document paths have an even number of segments
+	if rec.Path == "" || strings.Count(rec.Path, "/")%2 == 0 {
+		return obj, false, fmt.Errorf("invalid document path %q", rec.Path)
+	}
+
+	data, err := decodeData(DB.Client, rec.Data)
+	if err != nil {
+		return obj, false, fmt.Errorf("could not decode %s: %s", rec.Path, err.Error())
+	}
+
+	return db.Object{
+		Collection: path.Dir(rec.Path),
+		Doc:        path.Base(rec.Path),
+		Data:       document(data),
+	}, false, nil
+}
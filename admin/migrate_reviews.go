@@ -0,0 +1,73 @@
+// package admin contains maintenance tasks that are run from the admin command, see cmd/admin
+package admin
+
+import (
+	"fmt"
+	"log"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"google.golang.org/api/iterator"
+)
+
+// MigrateReviews converts the legacy subject stats to the per category format
+//
+// Legacy stats only store the number of reviews and the number of positive worth_it answers, so they are converted
+// into a count, a sum and a true/false histogram, merged with any categories counted since they were introduced.
+// Subjects without the legacy worth_it count were already migrated and are skipped. If dryRun is set, nothing is written.
+func MigrateReviews(DB db.Env, dryRun bool) (int, error) {
+	iter := DB.Client.Collection("subjects").Documents(DB.Ctx)
+	defer iter.Stop()
+
+	batch, migrated := newBatcher(DB), 0
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return migrated, fmt.Errorf("could not list subjects: %s", err.Error())
+		}
+
+		var sub models.Subject
+		if err := snap.DataTo(&sub); err != nil {
+			return migrated, fmt.Errorf("could not bind subject %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		if sub.Stats.LegacyWorthIt == nil {
+			continue
+		}
+
+		stats := migrateStats(sub.Stats)
+		log.Printf("migrating %s: %d reviews\n", snap.Ref.ID, stats.Total)
+		migrated++
+
+		if dryRun {
+			continue
+		}
+
+		// the whole map is replaced, which removes the legacy count
+		if err := batch.update(snap.Ref, []firestore.Update{{Path: "stats", Value: stats}}); err != nil {
+			return migrated, err
+		}
+	}
+
+	if err := batch.flush(); err != nil {
+		return migrated, err
+	}
+
+	return migrated, nil
+}
+
+// migrateStats builds the new subject stats from legacy ones, with an empty entry for every configured category
+func migrateStats(legacy models.SubjectStats) models.SubjectStats {
+	legacy.Normalize()
+
+	stats := models.NewSubjectStats()
+	stats.Total = legacy.Total
+	for name, s := range legacy.Categories {
+		stats.Categories[name] = s
+	}
+
+	return stats
+}
@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// record is a line of an export file: a document and its path, relative to the database root
+//
+// Values that JSON cannot represent are tagged objects with a single key:
+// {"$float": 1.0}, {"$time": "2021-08-01T00:00:00Z"}, {"$bytes": "<base64>"} and {"$ref": "<document path>"}.
+// Maps whose only key starts with "$" are wrapped as {"$map": {...}} so they are not mistaken for tags.
+type record struct {
+	Path string                 `json:"path"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// relativePath strips the project and database prefix from a document path
+func relativePath(ref *firestore.DocumentRef) string {
+	if i := strings.Index(ref.Path, "/documents/"); i >= 0 {
+		return ref.Path[i+len("/documents/"):]
+	}
+
+	return ref.Path
+}
+
+// encodeData converts the data of a document snapshot to JSON friendly values
+func encodeData(data map[string]interface{}) (map[string]interface{}, error) {
+	encoded := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		value, err := encodeValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s", k, err.Error())
+		}
+
+		encoded[k] = value
+	}
+
+	return encoded, nil
+}
+
+func encodeValue(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case nil, bool, string, int64:
+		return value, nil
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return map[string]interface{}{"$float": strconv.FormatFloat(value, 'g', -1, 64)}, nil
+		}
+
+		return map[string]interface{}{"$float": value}, nil
+	case time.Time:
+		return map[string]interface{}{"$time": value.UTC().Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return map[string]interface{}{"$bytes": base64.StdEncoding.EncodeToString(value)}, nil
+	case *firestore.DocumentRef:
+		return map[string]interface{}{"$ref": relativePath(value)}, nil
+	case []interface{}:
+		encoded := make([]interface{}, len(value))
+		for i, e := range value {
+			var err error
+			if encoded[i], err = encodeValue(e); err != nil {
+				return nil, err
+			}
+		}
+
+		return encoded, nil
+	case map[string]interface{}:
+		encoded, err := encodeData(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTagged(encoded) {
+			return map[string]interface{}{"$map": encoded}, nil
+		}
+
+		return encoded, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// isTagged reports whether a JSON object has the shape of a tagged value
+func isTagged(m map[string]interface{}) bool {
+	if len(m) != 1 {
+		return false
+	}
+
+	for k := range m {
+		return strings.HasPrefix(k, "$")
+	}
+
+	return false
+}
+
+// decodeData converts JSON values back to firestore values, refs are created with the given client
+//
+// The JSON must be decoded with UseNumber, so integers are kept apart from floats
+func decodeData(client *firestore.Client, data map[string]interface{}) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		value, err := decodeValue(client, v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s", k, err.Error())
+		}
+
+		decoded[k] = value
+	}
+
+	return decoded, nil
+}
+
+func decodeValue(client *firestore.Client, v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case nil, bool, string:
+		return value, nil
+	case json.Number:
+		return value.Int64()
+	case []interface{}:
+		decoded := make([]interface{}, len(value))
+		for i, e := range value {
+			var err error
+			if decoded[i], err = decodeValue(client, e); err != nil {
+				return nil, err
+			}
+		}
+
+		return decoded, nil
+	case map[string]interface{}:
+		if !isTagged(value) {
+			return decodeData(client, value)
+		}
+
+		return decodeTagged(client, value)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+func decodeTagged(client *firestore.Client, tagged map[string]interface{}) (interface{}, error) {
+	for tag, v := range tagged {
+		switch tag {
+		case "$float":
+			switch f := v.(type) {
+			case json.Number:
+				return f.Float64()
+			case string:
+				return strconv.ParseFloat(f, 64)
+			}
+		case "$time":
+			if s, ok := v.(string); ok {
+				return time.Parse(time.RFC3339Nano, s)
+			}
+		case "$bytes":
+			if s, ok := v.(string); ok {
+				return base64.StdEncoding.DecodeString(s)
+			}
+		case "$ref":
+			if s, ok := v.(string); ok {
+				if ref := client.Doc(s); ref != nil {
+					return ref, nil
+				}
+			}
+		case "$map":
+			if m, ok := v.(map[string]interface{}); ok {
+				return decodeData(client, m)
+			}
+		default:
+			return nil, fmt.Errorf("unknown tag %s", tag)
+		}
+
+		return nil, fmt.Errorf("invalid value for tag %s: %v", tag, v)
+	}
+
+	return nil, nil
+}
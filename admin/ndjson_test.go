@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	data := map[string]interface{}{
+		"name":    "Cálculo I",
+		"credits": int64(4),
+		"average": 7.0,
+		"ratio":   0.25,
+		"active":  true,
+		"removed": nil,
+		"updated": time.Date(2021, 8, 1, 12, 30, 0, 0, time.UTC),
+		"raw":     []byte{0, 1, 2},
+		"years":   []interface{}{"2020", int64(2021)},
+		"stats": map[string]interface{}{
+			"histogram": map[string]interface{}{"4": int64(1)},
+		},
+		"tricky": map[string]interface{}{"$float": "not a tag"},
+	}
+
+	encoded, err := encodeData(data)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, json.NewEncoder(&buf).Encode(record{Path: "subjects/abc", Data: encoded}))
+
+	decoder := json.NewDecoder(&buf)
+	decoder.UseNumber()
+
+	var rec record
+	require.NoError(t, decoder.Decode(&rec))
+	assert.Equal(t, "subjects/abc", rec.Path)
+
+	decoded, err := decodeData(nil, rec.Data)
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+}
+
+func TestAnonymize(t *testing.T) {
+	e := exporter{pseudonyms: map[string]string{"user": "pseudonym"}}
+
+	path, data := e.anonymize("users/user", map[string]interface{}{"name": "secret", "verified": true})
+	assert.Equal(t, "users/pseudonym", path)
+	assert.Equal(t, map[string]interface{}{"name": "", "email": "", "password": "", "verified": true}, data)
+
+	path, data = e.anonymize("subjects/s/offerings/o/comment_votes/user-3", map[string]interface{}{"counter": "user"})
+	assert.Equal(t, "subjects/s/offerings/o/comment_votes/pseudonym-3", path)
+	assert.Equal(t, map[string]interface{}{"counter": "pseudonym"}, data)
+}
+
+func TestDocumentSingleWrites(t *testing.T) {
+	// only batches know the path of imported documents
+	assert.ErrorIs(t, document{}.Insert(db.Env{}, "subjects"), errNoPath)
+	assert.ErrorIs(t, document{}.Update(db.Env{}, "subjects"), errNoPath)
+}
+
+func TestAnonymizedExportSkipsTranscripts(t *testing.T) {
+	users := &firestore.CollectionRef{ID: usersCollection}
+	subjects := &firestore.CollectionRef{ID: "subjects"}
+
+	transcript := &firestore.CollectionRef{ID: transcriptCollection, Parent: &firestore.DocumentRef{ID: "user", Parent: users}}
+	reviews := &firestore.CollectionRef{ID: "subject_reviews", Parent: &firestore.DocumentRef{ID: "user", Parent: users}}
+	other := &firestore.CollectionRef{ID: transcriptCollection, Parent: &firestore.DocumentRef{ID: "subject", Parent: subjects}}
+
+	e := exporter{opts: ExportOptions{AnonymizeUsers: true}}
+	assert.False(t, e.exportsSubcollection(transcript))
+	assert.True(t, e.exportsSubcollection(reviews))
+	assert.True(t, e.exportsSubcollection(other))
+
+	e = exporter{}
+	assert.True(t, e.exportsSubcollection(transcript))
+}
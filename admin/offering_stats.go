@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"google.golang.org/api/iterator"
+)
+
+// BackfillOfferingStats recomputes the rating stats of every offering from its comments
+//
+// Comments published while it runs may be counted twice or not at all, so it should run while comments are disabled
+// or be run again afterwards. If dryRun is set, nothing is written.
+func BackfillOfferingStats(DB db.Env, dryRun bool) (int, error) {
+	iter := DB.Client.CollectionGroup("offerings").Documents(DB.Ctx)
+	defer iter.Stop()
+
+	batch, updated := newBatcher(DB), 0
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return updated, fmt.Errorf("could not list offerings: %s", err.Error())
+		}
+
+		comments, err := snap.Ref.Collection("comments").Documents(DB.Ctx).GetAll()
+		if err != nil {
+			return updated, fmt.Errorf("could not list comments of %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		var stats models.OfferingStats
+		for _, c := range comments {
+			var comment models.Comment
+			if err := c.DataTo(&comment); err != nil {
+				return updated, fmt.Errorf("could not bind comment %s: %s", c.Ref.Path, err.Error())
+			}
+
+			stats.Add(comment.Rating)
+		}
+
+		log.Printf("offering %s: %+v\n", snap.Ref.Path, stats)
+		updated++
+
+		if dryRun {
+			continue
+		}
+
+		if err := batch.update(snap.Ref, []firestore.Update{{Path: "stats", Value: stats}}); err != nil {
+			return updated, err
+		}
+	}
+
+	if err := batch.flush(); err != nil {
+		return updated, err
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/Projeto-USPY/uspy-backend/search"
+	"google.golang.org/api/iterator"
+)
+
+// BuildProfessors rebuilds the professors collection from the offerings of every subject
+//
+// Offerings are only written by the scraper, so this must be run after every scraper update.
+// Professors that no longer have offerings are removed. If dryRun is set, nothing is written.
+func BuildProfessors(DB db.Env, dryRun bool) (int, error) {
+	professors := make(map[string]*models.Professor)
+	subjects := make(map[string]*models.Subject) // subject hash -> subject
+
+	iter := DB.Client.CollectionGroup("offerings").Documents(DB.Ctx)
+	defer iter.Stop()
+
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return 0, fmt.Errorf("could not list offerings: %s", err.Error())
+		}
+
+		// only offerings inside subjects/{hash}/offerings are considered
+		subRef := snap.Ref.Parent.Parent
+		if subRef == nil || subRef.Parent.ID != "subjects" {
+			continue
+		}
+
+		sub, ok := subjects[subRef.ID]
+		if !ok {
+			subSnap, err := subRef.Get(DB.Ctx)
+			if err != nil {
+				return 0, fmt.Errorf("could not get subject %s: %s", subRef.ID, err.Error())
+			}
+
+			sub = &models.Subject{}
+			if err := subSnap.DataTo(sub); err != nil {
+				return 0, fmt.Errorf("could not bind subject %s: %s", subRef.ID, err.Error())
+			}
+
+			subjects[subRef.ID] = sub
+		}
+
+		var off models.Offering
+		if err := snap.DataTo(&off); err != nil {
+			return 0, fmt.Errorf("could not bind offering %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		prof, ok := professors[snap.Ref.ID]
+		if !ok {
+			prof = &models.Professor{Hash: snap.Ref.ID, Offerings: make([]models.ProfessorOffering, 0)}
+			professors[snap.Ref.ID] = prof
+		}
+
+		if off.Professor != "" {
+			prof.Name = off.Professor
+		}
+
+		prof.Offerings = append(prof.Offerings, models.ProfessorOffering{
+			Subject:        sub.Code,
+			CourseCode:     sub.CourseCode,
+			Specialization: sub.Specialization,
+			SubjectName:    sub.Name,
+			Years:          off.Years,
+		})
+	}
+
+	batch := newBatcher(DB)
+	for _, prof := range professors {
+		prof.Tokens = search.Prefixes(prof.Name)
+		prof.SortOfferings()
+
+		logger.Info("built professor", logger.Fields{"professor": prof.Hash, "offerings": len(prof.Offerings)})
+		if dryRun {
+			continue
+		}
+
+		if err := batch.set(DB.Client.Collection("professors").Doc(prof.Hash), prof); err != nil {
+			return 0, err
+		}
+	}
+
+	// remove professors that are not in any offering anymore
+	refs, err := DB.Client.Collection("professors").DocumentRefs(DB.Ctx).GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("could not list professors: %s", err.Error())
+	}
+
+	for _, ref := range refs {
+		if _, ok := professors[ref.ID]; ok {
+			continue
+		}
+
+		logger.Info("removing professor", logger.Fields{"professor": ref.ID})
+		if dryRun {
+			continue
+		}
+
+		if err := batch.delete(ref); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := batch.flush(); err != nil {
+		return 0, err
+	}
+
+	return len(professors), nil
+}
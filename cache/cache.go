@@ -0,0 +1,209 @@
+/* package cache contains a read-through cache for data that rarely changes, such as the course and subject catalog */
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+)
+
+// Meta describes a cached value, it is used for HTTP revalidation
+type Meta struct {
+	ETag         string    // strong ETag, quoted, computed from the JSON encoding of the value
+	LastModified time.Time // when the value was loaded from the database, truncated to seconds
+}
+
+// NotModified reports whether a client holding a response with the given validators can reuse it
+//
+// If-None-Match takes precedence over If-Modified-Since, as in RFC 7232
+func (m Meta) NotModified(ifNoneMatch, ifModifiedSince string) bool {
+	if ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			if tag = strings.TrimSpace(tag); tag == m.ETag || tag == "*" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ifModifiedSince != "" {
+		if since, err := time.Parse(timeFormat, ifModifiedSince); err == nil {
+			return !m.LastModified.After(since)
+		}
+	}
+
+	return false
+}
+
+// HTTPTime formats LastModified for the Last-Modified header
+func (m Meta) HTTPTime() string {
+	return m.LastModified.UTC().Format(timeFormat)
+}
+
+// timeFormat is the format of HTTP dates
+const timeFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+// Entry is a serialized value, as stored in a Backend
+type Entry struct {
+	Value   []byte
+	Meta    Meta
+	Expires time.Time
+}
+
+// Backend is a cache shared between instances
+type Backend interface {
+	Get(ctx context.Context, key string) (*Entry, bool, error)
+	Set(ctx context.Context, key string, entry *Entry) error
+
+	// DeletePrefix removes every entry whose key starts with prefix, an empty prefix removes everything
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+type memoryEntry struct {
+	value   interface{}
+	meta    Meta
+	expires time.Time
+}
+
+// Cache keeps values in memory, and optionally in a shared backend, until they expire or are invalidated
+//
+// A nil *Cache is valid and caches nothing
+type Cache struct {
+	ttl     time.Duration
+	backend Backend
+
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+// New creates a cache whose entries live for ttl, backend may be nil
+func New(ttl time.Duration, backend Backend) *Cache {
+	return &Cache{ttl: ttl, backend: backend, entries: make(map[string]memoryEntry)}
+}
+
+func newMeta(encoded []byte) Meta {
+	sum := sha256.Sum256(encoded)
+	return Meta{
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Load returns the cached value for key, calling load on a miss
+//
+// Values are cached by their JSON encoding in the shared backend, so T must survive a JSON round trip.
+// Backend errors are logged and treated as misses, the database is always the source of truth
+func Load[T any](ctx context.Context, c *Cache, key string, load func() (T, error)) (T, Meta, error) {
+	if c != nil {
+		if value, meta, ok := c.getMemory(key); ok {
+			if v, ok := value.(T); ok {
+				metrics.hit(key, false)
+				return v, meta, nil
+			}
+		}
+
+		if value, meta, ok := loadShared[T](ctx, c, key); ok {
+			metrics.hit(key, true)
+			c.setMemory(key, value, meta)
+			return value, meta, nil
+		}
+	}
+
+	metrics.miss(key)
+	value, err := load()
+	if err != nil {
+		metrics.loadError(key)
+		return value, Meta{}, err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value, Meta{}, err
+	}
+
+	meta := newMeta(encoded)
+	if c != nil {
+		c.setMemory(key, value, meta)
+		if c.backend != nil {
+			entry := &Entry{Value: encoded, Meta: meta, Expires: time.Now().Add(c.ttl)}
+			if err := c.backend.Set(ctx, key, entry); err != nil {
+				metrics.backendError(key)
+				logger.FromContext(ctx).Warn("could not store entry in cache backend", logger.Fields{"key": key, "error": err})
+			}
+		}
+	}
+
+	return value, meta, nil
+}
+
+func loadShared[T any](ctx context.Context, c *Cache, key string) (T, Meta, bool) {
+	var value T
+	if c.backend == nil {
+		return value, Meta{}, false
+	}
+
+	entry, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		metrics.backendError(key)
+		logger.FromContext(ctx).Warn("could not get entry from cache backend", logger.Fields{"key": key, "error": err})
+		return value, Meta{}, false
+	} else if !ok || time.Now().After(entry.Expires) {
+		return value, Meta{}, false
+	}
+
+	if err := json.Unmarshal(entry.Value, &value); err != nil {
+		metrics.backendError(key)
+		logger.FromContext(ctx).Warn("could not decode entry from cache backend", logger.Fields{"key": key, "error": err})
+		return value, Meta{}, false
+	}
+
+	return value, entry.Meta, true
+}
+
+func (c *Cache) getMemory(key string) (interface{}, Meta, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, Meta{}, false
+	}
+
+	return e.value, e.meta, true
+}
+
+func (c *Cache) setMemory(key string, value interface{}, meta Meta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryEntry{value: value, meta: meta, expires: time.Now().Add(c.ttl)}
+}
+
+// Invalidate removes every entry whose key starts with prefix, from memory and from the shared backend
+func (c *Cache) Invalidate(ctx context.Context, prefix string) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+
+	metrics.invalidation(prefix)
+	if c.backend != nil {
+		return c.backend.DeletePrefix(ctx, prefix)
+	}
+
+	return nil
+}
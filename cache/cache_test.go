@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackend is a Backend kept in a map, standing in for firestore
+type memoryBackend struct {
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+func (b *memoryBackend) Get(_ context.Context, key string) (*Entry, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, ok := b.entries[key]
+	return e, ok, nil
+}
+
+func (b *memoryBackend) Set(_ context.Context, key string, entry *Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[key] = entry
+	return nil
+}
+
+func (b *memoryBackend) DeletePrefix(_ context.Context, prefix string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key := range b.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(b.entries, key)
+		}
+	}
+
+	return nil
+}
+
+// counter returns a loader that counts its calls
+func counter(value []string) (func() ([]string, error), *int) {
+	calls := 0
+	return func() ([]string, error) {
+		calls++
+		return value, nil
+	}, &calls
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, nil)
+	load, calls := counter([]string{"SCC0222"})
+
+	first, meta, err := Load(ctx, c, "courses", load)
+	require.NoError(t, err)
+	second, again, err := Load(ctx, c, "courses", load)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, first, second)
+	assert.Equal(t, meta, again)
+	assert.NotEmpty(t, meta.ETag)
+
+	t.Run("nil cache", func(t *testing.T) {
+		load, calls := counter(nil)
+		for i := 0; i < 2; i++ {
+			_, _, err := Load[[]string](ctx, nil, "courses", load)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		calls := 0
+		load := func() (int, error) {
+			calls++
+			return 0, errors.New("unavailable")
+		}
+
+		for i := 0; i < 2; i++ {
+			_, _, err := Load(ctx, c, "failing", load)
+			assert.Error(t, err)
+		}
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c := New(time.Nanosecond, nil)
+		load, calls := counter(nil)
+		for i := 0; i < 2; i++ {
+			_, _, err := Load(ctx, c, "courses", load)
+			require.NoError(t, err)
+			time.Sleep(time.Millisecond)
+		}
+
+		assert.Equal(t, 2, *calls)
+	})
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, nil)
+	load, calls := counter(nil)
+
+	for _, key := range []string{"subject/a", "subject/b", "courses"} {
+		_, _, err := Load(ctx, c, key, load)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, c.Invalidate(ctx, "subject/"))
+	for _, key := range []string{"subject/a", "subject/b", "courses"} {
+		_, _, err := Load(ctx, c, key, load)
+		require.NoError(t, err)
+	}
+
+	// only the subjects were loaded again
+	assert.Equal(t, 5, *calls)
+}
+
+func TestSharedBackend(t *testing.T) {
+	ctx := context.Background()
+	backend := &memoryBackend{entries: make(map[string]*Entry)}
+	a, b := New(time.Hour, backend), New(time.Hour, backend)
+	load, calls := counter([]string{"SCC0222", "SCC0217"})
+
+	fromA, metaA, err := Load(ctx, a, "courses", load)
+	require.NoError(t, err)
+	fromB, metaB, err := Load(ctx, b, "courses", load)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, fromA, fromB)
+	assert.Equal(t, metaA.ETag, metaB.ETag)
+
+	require.NoError(t, a.Invalidate(ctx, "courses"))
+	assert.Empty(t, backend.entries)
+}
+
+func TestNotModified(t *testing.T) {
+	meta := Meta{ETag: `"abc"`, LastModified: time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name            string
+		ifNoneMatch     string
+		ifModifiedSince string
+		want            bool
+	}{
+		{"no validators", "", "", false},
+		{"matching etag", `"abc"`, "", true},
+		{"one of many etags", `"xyz", "abc"`, "", true},
+		{"other etag", `"xyz"`, "", false},
+		{"etag takes precedence", `"xyz"`, meta.HTTPTime(), false},
+		{"not modified since", "", meta.HTTPTime(), true},
+		{"modified since", "", "Thu, 30 Sep 2021 12:00:00 GMT", false},
+		{"invalid date", "", "yesterday", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, meta.NotModified(tt.ifNoneMatch, tt.ifModifiedSince))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FirestoreBackend shares cache entries between instances through a firestore collection
+//
+// Reading one entry is much cheaper than reading every document it was built from, e.g. every course
+type FirestoreBackend struct {
+	Collection *firestore.CollectionRef
+}
+
+// firestoreEntry is how an Entry is stored, keys cannot be used as document IDs because they contain "/"
+type firestoreEntry struct {
+	Key          string    `firestore:"key"`
+	Value        []byte    `firestore:"value"`
+	ETag         string    `firestore:"etag"`
+	LastModified time.Time `firestore:"last_modified"`
+	Expires      time.Time `firestore:"expires"`
+}
+
+func (b FirestoreBackend) doc(key string) *firestore.DocumentRef {
+	return b.Collection.Doc(strings.ReplaceAll(key, "/", "|"))
+}
+
+func (b FirestoreBackend) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	snap, err := b.doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var stored firestoreEntry
+	if err := snap.DataTo(&stored); err != nil {
+		return nil, false, err
+	}
+
+	return &Entry{
+		Value:   stored.Value,
+		Meta:    Meta{ETag: stored.ETag, LastModified: stored.LastModified},
+		Expires: stored.Expires,
+	}, true, nil
+}
+
+func (b FirestoreBackend) Set(ctx context.Context, key string, entry *Entry) error {
+	_, err := b.doc(key).Set(ctx, firestoreEntry{
+		Key:          key,
+		Value:        entry.Value,
+		ETag:         entry.Meta.ETag,
+		LastModified: entry.Meta.LastModified,
+		Expires:      entry.Expires,
+	})
+
+	return err
+}
+
+func (b FirestoreBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	query := b.Collection.Query
+	if prefix != "" {
+		query = query.Where("key", ">=", prefix).Where("key", "<", prefix+"\uf8ff")
+	}
+
+	refs, err := query.Select().Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, snap := range refs {
+		if _, err := snap.Ref.Delete(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
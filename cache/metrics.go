@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"expvar"
+	"strings"
+)
+
+// Counters are kept per key namespace (the key up to its first "/") and exported with expvar under "cache"
+var (
+	hits          = new(expvar.Map) // served from memory
+	sharedHits    = new(expvar.Map) // served from the shared backend
+	misses        = new(expvar.Map) // loaded from the database
+	loadErrors    = new(expvar.Map) // database loads that failed
+	backendErrors = new(expvar.Map) // shared backend operations that failed
+	invalidations = new(expvar.Map)
+)
+
+func init() {
+	m := expvar.NewMap("cache")
+	m.Set("hits", hits)
+	m.Set("shared_hits", sharedHits)
+	m.Set("misses", misses)
+	m.Set("load_errors", loadErrors)
+	m.Set("backend_errors", backendErrors)
+	m.Set("invalidations", invalidations)
+}
+
+type recorder struct{}
+
+var metrics recorder
+
+// namespace groups keys such as subject/<hash> so counters do not grow with the catalog
+func namespace(key string) string {
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i]
+	}
+
+	if key == "" {
+		return "all"
+	}
+
+	return key
+}
+
+func (recorder) hit(key string, shared bool) {
+	if shared {
+		sharedHits.Add(namespace(key), 1)
+	} else {
+		hits.Add(namespace(key), 1)
+	}
+}
+
+func (recorder) miss(key string)         { misses.Add(namespace(key), 1) }
+func (recorder) loadError(key string)    { loadErrors.Add(namespace(key), 1) }
+func (recorder) backendError(key string) { backendErrors.Add(namespace(key), 1) }
+func (recorder) invalidation(key string) { invalidations.Add(namespace(key), 1) }
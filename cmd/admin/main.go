@@ -0,0 +1,203 @@
+// package main runs maintenance tasks against the database
+//
+// Usage:
+//
+//	admin migrate-reviews [-dry-run]
+//	admin build-professors [-dry-run]
+//	admin backfill-offering-stats [-dry-run]
+//	admin check-consistency [-checks votes,stats,grades] [-repair] [-dry-run]
+//	admin export [-out file] [-collections subjects,courses] [-skip-users] [-anonymize-users]
+//	admin import [-in file] [-skip-users] [-dry-run]
+//	admin invalidate-cache [-prefix key]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Projeto-USPY/uspy-backend/admin"
+	"github.com/Projeto-USPY/uspy-backend/cache"
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: admin <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  migrate-reviews    convert legacy subject review stats to the per category format")
+	fmt.Fprintln(os.Stderr, "  build-professors   rebuild the professors collection from the subject offerings")
+	fmt.Fprintln(os.Stderr, "  backfill-offering-stats")
+	fmt.Fprintln(os.Stderr, "                     recompute the rating stats of every offering from its comments")
+	fmt.Fprintln(os.Stderr, "  check-consistency  compare denormalized votes, review stats and grades with their sources")
+	fmt.Fprintln(os.Stderr, "  export             write every document to NDJSON")
+	fmt.Fprintln(os.Stderr, "  import             write the documents of an NDJSON export to the database")
+	fmt.Fprintln(os.Stderr, "  invalidate-cache   remove shared catalog cache entries")
+}
+
+func migrateReviews(args []string) {
+	fs := flag.NewFlagSet("migrate-reviews", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "only list the subjects that would be migrated")
+	_ = fs.Parse(args)
+
+	DB := db.SetupDB()
+	migrated, err := admin.MigrateReviews(DB, *dryRun)
+	if err != nil {
+		logger.Fatal("could not migrate reviews", logger.Fields{"error": err})
+	}
+
+	logger.Info("migrated subjects", logger.Fields{"subjects": migrated})
+}
+
+func buildProfessors(args []string) {
+	fs := flag.NewFlagSet("build-professors", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "only list the professors that would be written or removed")
+	_ = fs.Parse(args)
+
+	DB := db.SetupDB()
+	built, err := admin.BuildProfessors(DB, *dryRun)
+	if err != nil {
+		logger.Fatal("could not build professors", logger.Fields{"error": err})
+	}
+
+	logger.Info("built professors", logger.Fields{"professors": built})
+}
+
+func backfillOfferingStats(args []string) {
+	fs := flag.NewFlagSet("backfill-offering-stats", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "only print the stats that would be written")
+	_ = fs.Parse(args)
+
+	DB := db.SetupDB()
+	updated, err := admin.BackfillOfferingStats(DB, *dryRun)
+	if err != nil {
+		logger.Fatal("could not backfill offering stats", logger.Fields{"error": err})
+	}
+
+	logger.Info("updated offerings", logger.Fields{"offerings": updated})
+}
+
+func checkConsistency(args []string) {
+	fs := flag.NewFlagSet("check-consistency", flag.ExitOnError)
+	checks := fs.String("checks", strings.Join(admin.AllChecks, ","), "comma separated list of checks to run")
+	repair := fs.Bool("repair", false, "recompute the denormalized values that do not match their sources")
+	dryRun := fs.Bool("dry-run", false, "only print the repairs that would be written")
+	_ = fs.Parse(args)
+
+	DB := db.SetupDB()
+	found, err := admin.CheckConsistency(DB, strings.Split(*checks, ","), *repair, *dryRun)
+	if err != nil {
+		logger.Fatal("could not check consistency", logger.Fields{"error": err})
+	}
+
+	logger.Info("checked consistency", logger.Fields{"discrepancies": len(found)})
+	if len(found) > 0 && !*repair {
+		os.Exit(1)
+	}
+}
+
+func export(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("out", "", "file to write the export to, defaults to stdout")
+	collections := fs.String("collections", "", "comma separated list of root collections to export, defaults to all")
+	skipUsers := fs.Bool("skip-users", false, "do not export the users collection")
+	anonymizeUsers := fs.Bool("anonymize-users", false, "replace user hashes with pseudonyms, clear personal data and leave transcripts out")
+	_ = fs.Parse(args)
+
+	opts := admin.ExportOptions{SkipUsers: *skipUsers, AnonymizeUsers: *anonymizeUsers}
+	if *collections != "" {
+		opts.Collections = strings.Split(*collections, ",")
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			logger.Fatal("could not create export file", logger.Fields{"error": err})
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	DB := db.SetupDB()
+	exported, err := admin.Export(DB, w, opts)
+	if err != nil {
+		logger.Fatal("could not export documents", logger.Fields{"error": err})
+	}
+
+	logger.Info("exported documents", logger.Fields{"documents": exported})
+}
+
+func importExport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	in := fs.String("in", "", "file to read the export from, defaults to stdin")
+	skipUsers := fs.Bool("skip-users", false, "do not import the users collection")
+	dryRun := fs.Bool("dry-run", false, "only parse the export")
+	_ = fs.Parse(args)
+
+	r := os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			logger.Fatal("could not open export file", logger.Fields{"error": err})
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	DB := db.SetupDB()
+	imported, err := admin.Import(DB, r, admin.ImportOptions{SkipUsers: *skipUsers, DryRun: *dryRun})
+	if err != nil {
+		logger.Fatal("could not import documents", logger.Fields{"error": err})
+	}
+
+	logger.Info("imported documents", logger.Fields{"documents": imported})
+}
+
+func invalidateCache(args []string) {
+	fs := flag.NewFlagSet("invalidate-cache", flag.ExitOnError)
+	prefix := fs.String("prefix", "", "only remove entries whose keys start with this prefix, e.g. courses or subject/")
+	_ = fs.Parse(args)
+
+	DB := db.SetupDB()
+	backend := cache.FirestoreBackend{Collection: DB.Client.Collection(db.CacheCollection)}
+	if err := backend.DeletePrefix(DB.Ctx, *prefix); err != nil {
+		logger.Fatal("could not invalidate cache", logger.Fields{"error": err})
+	}
+
+	logger.Info("invalidated shared cache entries", logger.Fields{"prefix": *prefix})
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	config.Setup()
+
+	switch os.Args[1] {
+	case "migrate-reviews":
+		migrateReviews(os.Args[2:])
+	case "build-professors":
+		buildProfessors(os.Args[2:])
+	case "backfill-offering-stats":
+		backfillOfferingStats(os.Args[2:])
+	case "check-consistency":
+		checkConsistency(os.Args[2:])
+	case "export":
+		export(os.Args[2:])
+	case "import":
+		importExport(os.Args[2:])
+	case "invalidate-cache":
+		invalidateCache(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Catalog cache backends
+const (
+	CacheMemory    = "memory"    // each instance only keeps its own in-memory cache
+	CacheFirestore = "firestore" // instances also share entries stored in firestore
+)
+
+// CatalogCache configures the read-through cache for course and subject catalog data
+type CatalogCache struct {
+	TTL     time.Duration `envconfig:"USPY_CACHE_TTL" default:"1h"` // 0 disables the cache
+	Backend string        `envconfig:"USPY_CACHE_BACKEND" default:"memory"`
+}
+
+// Setup checks the cache backend
+func (c CatalogCache) Setup() error {
+	if c.Backend != CacheMemory && c.Backend != CacheFirestore {
+		return fmt.Errorf("unknown cache backend %q", c.Backend)
+	}
+
+	return nil
+}
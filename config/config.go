@@ -1,10 +1,15 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
 
 	"github.com/Projeto-USPY/uspy-backend/utils"
-	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -17,17 +22,29 @@ type GeneralConfig interface {
 // Configuration object, for more info see README.md
 type Config struct {
 	Domain    string `envconfig:"USPY_DOMAIN" required:"true" default:"localhost"`
-	Port      string `envconfig:"USPY_PORT" required:"true" default:"8080"` // careful with this because cloud run must run on port 8080
-	JWTSecret string `envconfig:"USPY_JWT_SECRET" required:"true" default:"my_secret"`
+	Port      string `envconfig:"USPY_PORT" required:"true" default:"8080"`            // careful with this because cloud run must run on port 8080
+	JWTSecret string `envconfig:"USPY_JWT_SECRET" required:"true" default:"my_secret"` // see DefaultJWTSecret
 	Mode      string `envconfig:"USPY_MODE" required:"true" default:"local"`
-	AESKey    string `envconfig:"USPY_AES_KEY" required:"true" default:"71deb5a48500599862d9e2170a60f90194a49fa81c24eacfe9da15cb76ba8b11"` // only used in dev
+	AESKey    string `envconfig:"USPY_AES_KEY" required:"true" default:"71deb5a48500599862d9e2170a60f90194a49fa81c24eacfe9da15cb76ba8b11"` // only used outside production, see DefaultAESKey
 	RateLimit string `envconfig:"USPY_RATE_LIMIT"`                                                                                         // see github.com/ulule/limiter for more info
 
+	RequestTimeout time.Duration `envconfig:"USPY_REQUEST_TIMEOUT" default:"15s"` // 0 disables the timeout
+	SwaggerUI      bool          `envconfig:"USPY_SWAGGER_UI" default:"false"`    // serves Swagger UI at /docs
+	MetricsToken   string        `envconfig:"USPY_METRICS_TOKEN"`                 // bearer token required to scrape /metrics, if set
+	MaxPDFAge      time.Duration `envconfig:"USPY_MAX_PDF_AGE"`                   // older transcripts are refused at signup, defaults to the profile's
+
 	FirestoreKeyPath string `envconfig:"USPY_FIRESTORE_KEY"`
 
 	ProjectID string `envconfig:"USPY_PROJECT_ID"`
 
-	Mailjet // email verification is needed in production
+	Frontend     // front-end URL, CORS origins and cookie domain
+	Mailjet      // email verification is needed in production
+	Reviews      // subject review categories
+	Privacy      // k-anonymity policies for aggregate statistics
+	CatalogCache // read-through cache for catalog data
+	Logging      // level and format of log entries
+	Tracing      // OpenTelemetry exporter
+	HTTPServer   // server timeouts and graceful shutdown
 }
 
 func (c Config) IsUsingKey() bool {
@@ -46,12 +63,16 @@ func (c Config) Identify() string {
 	}
 }
 
+func (c Config) IsProd() bool {
+	return c.Mode == ModeProd
+}
+
 func (c Config) IsDev() bool {
-	return c.Mode == "dev"
+	return c.Mode == ModeDev
 }
 
 func (c Config) IsLocal() bool {
-	return c.Mode == "local"
+	return c.Mode == ModeLocal
 }
 
 // Redact can be used to print the environment config without exposing secret
@@ -63,44 +84,101 @@ func (c Config) Redact() Config {
 	c.ProjectID = "[REDACTED]"
 	c.Mailjet.APIKey = "[REDACTED]"
 	c.Mailjet.Secret = "[REDACTED]"
+	c.MetricsToken = "[REDACTED]"
 	return c
 }
 
 // TestSetup is used by the emulator, it will only load required defaults, no project-related identifiers
 func TestSetup() {
 	if err := envconfig.Process("uspy", &Env); err != nil {
-		log.Fatal("could not process default env variables: ", err)
+		logger.Fatal("could not process default env variables", logger.Fields{"error": err})
 	}
+	Env.applyProfile()
 
-	log.Printf("env variables set: %#v\n", Env)
+	if err := Env.Logging.Setup(); err != nil {
+		logger.Fatal("could not setup logging", logger.Fields{"error": err})
+	}
+
+	if err := Env.Reviews.Setup(); err != nil {
+		logger.Fatal("could not setup review categories", logger.Fields{"error": err})
+	}
+
+	if err := Env.Privacy.Setup(); err != nil {
+		logger.Fatal("could not setup privacy policies", logger.Fields{"error": err})
+	}
+
+	if err := Env.CatalogCache.Setup(); err != nil {
+		logger.Fatal("could not setup catalog cache", logger.Fields{"error": err})
+	}
+
+	if err := Env.Tracing.Setup(); err != nil {
+		logger.Fatal("could not setup tracing", logger.Fields{"error": err})
+	}
+
+	if err := Env.HTTPServer.Setup(); err != nil {
+		logger.Fatal("could not setup http server", logger.Fields{"error": err})
+	}
+
+	if err := Env.Validate(); err != nil {
+		logger.Fatal("invalid configuration", logger.Fields{"error": err})
+	}
+
+	logger.Info("env variables set", logger.Fields{"env": fmt.Sprintf("%#v", Env.Redact())})
 }
 
-// Setup parses the .env file (or uses defaults) to determine environment constants and variables
+// Setup loads the configuration from the config file, environment variables and command-line flags (see Load), then validates it
 func Setup() {
-	err := godotenv.Load(".env")
-	if err != nil {
-		log.Println("did not parse .env file, falling to default env variables")
+	if err := Load(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+
+		logger.Fatal("could not load configuration", logger.Fields{"error": err})
 	}
 
-	if err := envconfig.Process("uspy", &Env); err != nil {
-		log.Fatal("could not process default env variables: ", err)
+	if err := Env.Logging.Setup(); err != nil {
+		logger.Fatal("could not setup logging", logger.Fields{"error": err})
+	}
+
+	logger.Info("env variables set", logger.Fields{"env": fmt.Sprintf("%#v", Env.Redact())})
+
+	if err := Env.Reviews.Setup(); err != nil {
+		logger.Fatal("could not setup review categories", logger.Fields{"error": err})
+	}
+
+	if err := Env.Privacy.Setup(); err != nil {
+		logger.Fatal("could not setup privacy policies", logger.Fields{"error": err})
+	}
+
+	if err := Env.CatalogCache.Setup(); err != nil {
+		logger.Fatal("could not setup catalog cache", logger.Fields{"error": err})
+	}
+
+	if err := Env.Tracing.Setup(); err != nil {
+		logger.Fatal("could not setup tracing", logger.Fields{"error": err})
+	}
+
+	if err := Env.HTTPServer.Setup(); err != nil {
+		logger.Fatal("could not setup http server", logger.Fields{"error": err})
 	}
 
-	log.Printf("env variables set: %#v\n", Env.Redact())
+	if err := Env.Validate(); err != nil {
+		logger.Fatal("invalid configuration", logger.Fields{"error": err})
+	}
 
 	if Env.IsUsingKey() {
-		log.Println("Running backend with firestore key")
+		logger.Info("running backend with firestore key")
 
 		if !utils.CheckFileExists(Env.FirestoreKeyPath) {
-			log.Fatal("Could not find firestore key path: ", Env.FirestoreKeyPath)
+			logger.Fatal("could not find firestore key path", logger.Fields{"path": Env.FirestoreKeyPath})
 		}
 	} else if Env.IsUsingProjectID() {
-		log.Println("Running backend with project ID")
+		logger.Info("running backend with project ID")
 
 		// setup email client
 		Env.Mailjet.Setup()
 	} else {
-		log.Fatal("Could not initialize backend because neither the Firestore Key nor the Project ID were specified")
+		logger.Fatal("could not initialize backend because neither the Firestore Key nor the Project ID were specified")
 	}
 
 }
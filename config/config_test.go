@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unset removes the variables until the end of the test
+func unset(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	previous := Env
+	defer func() { Env = previous }()
+
+	file := filepath.Join(t.TempDir(), "uspy.env")
+	require.NoError(t, os.WriteFile(file, []byte("USPY_PORT=7000\nUSPY_RATE_LIMIT=10-M\nUSPY_MODE=dev\n"), 0o600))
+
+	unset(t, "USPY_PORT", "USPY_MODE") // set by the file
+	t.Setenv("USPY_CONFIG_FILE", file)
+	t.Setenv("USPY_RATE_LIMIT", "20-M")
+	t.Setenv("USPY_SWAGGER_UI", "false")
+
+	require.NoError(t, Load([]string{"-swagger-ui", "true"}))
+
+	assert.Equal(t, "7000", Env.Port, "file")
+	assert.Equal(t, "20-M", Env.RateLimit, "env overrides file")
+	assert.True(t, Env.SwaggerUI, "flags override env")
+	assert.Equal(t, 15*time.Second, Env.RequestTimeout, "tag default")
+
+	assert.Equal(t, ModeDev, Env.Mode)
+	assert.Equal(t, "https://frontdev.uspy.me", Env.FrontendURL, "profile")
+	assert.Equal(t, []string{"https://frontdev.uspy.me"}, Env.AllowedOrigins)
+	assert.Equal(t, 30*24*time.Hour, Env.MaxPDFAge)
+}
+
+func TestLoadErrors(t *testing.T) {
+	previous := Env
+	defer func() { Env = previous }()
+
+	t.Setenv("USPY_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.env"))
+	assert.Error(t, Load(nil), "config files that were asked for must exist")
+
+	assert.Error(t, Load([]string{"-no-such-flag", "1"}))
+}
+
+func TestValidate(t *testing.T) {
+	unset(t, "USPY_JWT_SECRET", "USPY_AES_KEY", "USPY_MODE")
+
+	var defaults Config
+	require.NoError(t, envconfig.Process("uspy", &defaults))
+	assert.Equal(t, DefaultJWTSecret, defaults.JWTSecret)
+	assert.Equal(t, DefaultAESKey, defaults.AESKey)
+
+	valid := func(mode string) Config {
+		c := defaults
+		c.Mode = mode
+		c.FrontendURL, c.AllowedOrigins, c.CookieDomain, c.MaxPDFAge = "", nil, "", 0
+		c.applyProfile()
+		return c
+	}
+
+	assert.NoError(t, valid(ModeLocal).Validate())
+	assert.NoError(t, valid(ModeDev).Validate())
+
+	prod := valid(ModeProd)
+	err := prod.Validate()
+	if assert.Error(t, err, "default secrets are refused in production") {
+		assert.Contains(t, err.Error(), "USPY_JWT_SECRET")
+		assert.Contains(t, err.Error(), "USPY_AES_KEY")
+	}
+
+	prod.JWTSecret = "a long and random secret"
+	prod.AESKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	assert.NoError(t, prod.Validate())
+
+	invalid := prod
+	invalid.FrontendURL = "http://uspy.me"
+	invalid.AllowedOrigins = []string{"https://uspy.me/path"}
+	invalid.Port = "http"
+	invalid.WriteTimeout = invalid.RequestTimeout
+	err = invalid.Validate()
+	if assert.Error(t, err) {
+		for _, key := range []string{"USPY_FRONTEND_URL", "USPY_ALLOWED_ORIGINS", "USPY_PORT", "USPY_WRITE_TIMEOUT"} {
+			assert.Contains(t, err.Error(), key, "every problem is reported")
+		}
+	}
+
+	unknown := valid("staging")
+	assert.Error(t, unknown.Validate())
+}
@@ -0,0 +1,10 @@
+package config
+
+// Frontend holds the addresses of the front-end, used for CORS, cookies and links sent by email
+//
+// Empty fields are filled by the profile of the mode
+type Frontend struct {
+	FrontendURL    string   `envconfig:"USPY_FRONTEND_URL"`
+	AllowedOrigins []string `envconfig:"USPY_ALLOWED_ORIGINS"` // defaults to the frontend URL
+	CookieDomain   string   `envconfig:"USPY_COOKIE_DOMAIN"`
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/joho/godotenv"
+	"github.com/kelseyhightower/envconfig"
+)
+
+// DefaultConfigFile is read if it exists, other files must be given by USPY_CONFIG_FILE or -config
+const DefaultConfigFile = ".env"
+
+// Load fills Env from every configuration source, from lowest to highest precedence:
+//
+//  1. the defaults in the struct tags, then the profile of USPY_MODE for the fields without one (see profile.go)
+//  2. the config file, in .env format
+//  3. environment variables
+//  4. command-line flags, named after the variables without the prefix, e.g. -jwt-secret for USPY_JWT_SECRET
+//
+// args are the command-line arguments without the program name, parsing stops at the first non-flag argument
+func Load(args []string) error {
+	flags, file, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	// flags are exported first because godotenv never overrides variables that are already set
+	for key, value := range flags {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+
+	if err := godotenv.Load(file); err != nil {
+		if file != DefaultConfigFile || !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("could not read config file %s: %s", file, err.Error())
+		}
+
+		logger.Info("did not parse .env file, falling to default env variables")
+	}
+
+	if err := envconfig.Process("uspy", &Env); err != nil {
+		return err
+	}
+
+	Env.applyProfile()
+	return nil
+}
+
+// parseFlags returns the value of each variable set by a flag and the config file to read
+func parseFlags(args []string) (map[string]string, string, error) {
+	set := flag.NewFlagSet("uspy-backend", flag.ContinueOnError)
+
+	file := set.String("config", "", "config file in .env format, overrides USPY_CONFIG_FILE (default "+DefaultConfigFile+")")
+	values := make(map[string]*string)
+	for _, key := range variables(reflect.TypeOf(Config{})) {
+		values[key] = set.String(flagName(key), "", "overrides "+key)
+	}
+
+	if err := set.Parse(args); err != nil {
+		return nil, "", err
+	}
+
+	flags := make(map[string]string)
+	set.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+
+		key := "USPY_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		flags[key] = *values[key]
+	})
+
+	switch {
+	case *file != "":
+		return flags, *file, nil
+	case os.Getenv("USPY_CONFIG_FILE") != "":
+		return flags, os.Getenv("USPY_CONFIG_FILE"), nil
+	default:
+		return flags, DefaultConfigFile, nil
+	}
+}
+
+// variables lists the environment variables of the fields of t, including those of embedded structs
+func variables(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			keys = append(keys, variables(field.Type)...)
+		} else if key := field.Tag.Get("envconfig"); key != "" && field.IsExported() {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// flagName converts USPY_JWT_SECRET into jwt-secret
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(key, "USPY_"), "_", "-"))
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+)
+
+// Logging configures the default logger
+type Logging struct {
+	LogLevel  string `envconfig:"USPY_LOG_LEVEL" default:"info"`
+	LogFormat string `envconfig:"USPY_LOG_FORMAT" default:"json"` // text is easier to read locally
+}
+
+// Setup replaces the default logger with one at the configured level and format
+func (l Logging) Setup() error {
+	level, err := logger.ParseLevel(l.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	if l.LogFormat != logger.FormatJSON && l.LogFormat != logger.FormatText {
+		return fmt.Errorf("unknown log format %q", l.LogFormat)
+	}
+
+	logger.SetDefault(logger.New(os.Stderr, level, l.LogFormat))
+	return nil
+}
@@ -1,22 +1,21 @@
 package config
 
 import (
-	"log"
-
+	"github.com/Projeto-USPY/uspy-backend/logger"
 	"github.com/mailjet/mailjet-apiv3-go"
 )
 
 type Mailjet struct {
 	APIKey string `envconfig:"USPY_MAILJET_KEY"`
 	Secret string `envconfig:"USPY_MAILJET_SECRET"`
+	Sender string `envconfig:"USPY_MAIL_SENDER" default:"no-reply@uspy.me"`
 
 	client *mailjet.Client
 }
 
 // Email defaults
 const (
-	Sender = `no-reply@uspy.me`
-	Name   = `USPY`
+	Name = `USPY`
 )
 
 // Verification
@@ -43,15 +42,20 @@ func (m *Mailjet) Setup() {
 	if m.APIKey != "" && m.Secret != "" {
 		m.client = mailjet.NewMailjetClient(m.APIKey, m.Secret)
 	} else {
-		log.Println("failed to configure email client")
+		logger.Warn("failed to configure email client")
 	}
 }
 
+// Configured tells whether emails can be sent
+func (m *Mailjet) Configured() bool {
+	return m.client != nil
+}
+
 func (m *Mailjet) Send(target, subject, content string) error {
 	messagesInfo := []mailjet.InfoMessagesV31{
 		{
 			From: &mailjet.RecipientV31{
-				Email: Sender,
+				Email: m.Sender,
 				Name:  Name,
 			},
 			To: &mailjet.RecipientsV31{
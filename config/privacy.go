@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Statistics that are subject to a privacy policy
+const (
+	StatGrades    = "grades"    // grade distributions, one contributor per grade
+	StatOfferings = "offerings" // offering approval, one contributor per comment
+	StatReviews   = "reviews"   // subject review stats, one contributor per review
+)
+
+// Privacy actions, taken when a statistic has fewer than K contributors
+const (
+	PrivacySuppress = "suppress" // the statistic is not shown at all
+	PrivacyCoarsen  = "coarsen"  // counts, histograms and rates are dropped, averages only tell which half of the scale they are in
+)
+
+// PrivacyPolicy is the k-anonymity rule applied to a statistic
+type PrivacyPolicy struct {
+	K      int
+	Action string
+}
+
+// Privacy holds a policy for each statistic, written as `K:action` strings
+//
+// Policies are always enforced in production, in other modes only if Enforce is set
+type Privacy struct {
+	Enforce         bool   `envconfig:"USPY_PRIVACY_ENFORCE"`
+	GradesPolicy    string `envconfig:"USPY_PRIVACY_GRADES" default:"11:suppress"`
+	OfferingsPolicy string `envconfig:"USPY_PRIVACY_OFFERINGS" default:"5:suppress"`
+	ReviewsPolicy   string `envconfig:"USPY_PRIVACY_REVIEWS" default:"5:suppress"`
+
+	policies map[string]PrivacyPolicy
+}
+
+// Setup parses the policy of each statistic
+func (p *Privacy) Setup() error {
+	p.policies = make(map[string]PrivacyPolicy)
+
+	for stat, value := range map[string]string{
+		StatGrades:    p.GradesPolicy,
+		StatOfferings: p.OfferingsPolicy,
+		StatReviews:   p.ReviewsPolicy,
+	} {
+		policy, err := parsePrivacyPolicy(value)
+		if err != nil {
+			return fmt.Errorf("invalid privacy policy for %s: %s", stat, err.Error())
+		}
+
+		p.policies[stat] = policy
+	}
+
+	return nil
+}
+
+// Policy returns the policy of the given statistic
+//
+// Statistics without a policy (or before Setup) are never changed
+func (p Privacy) Policy(stat string) PrivacyPolicy {
+	if policy, ok := p.policies[stat]; ok {
+		return policy
+	}
+
+	return PrivacyPolicy{Action: PrivacySuppress}
+}
+
+func parsePrivacyPolicy(value string) (PrivacyPolicy, error) {
+	fields := strings.Split(value, ":")
+	if len(fields) != 2 {
+		return PrivacyPolicy{}, fmt.Errorf("expected K:action, got %q", value)
+	}
+
+	k, err := strconv.Atoi(fields[0])
+	if err != nil || k < 0 {
+		return PrivacyPolicy{}, fmt.Errorf("K must be a non-negative integer, got %q", fields[0])
+	}
+
+	switch fields[1] {
+	case PrivacySuppress, PrivacyCoarsen:
+	default:
+		return PrivacyPolicy{}, fmt.Errorf("unknown action %q", fields[1])
+	}
+
+	return PrivacyPolicy{K: k, Action: fields[1]}, nil
+}
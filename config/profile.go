@@ -0,0 +1,61 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// Modes the server runs in, see USPY_MODE
+const (
+	ModeProd  = "prod"
+	ModeDev   = "dev"
+	ModeLocal = "local"
+)
+
+// Profile holds the defaults that depend on the mode
+type Profile struct {
+	FrontendURL  string
+	CookieDomain string
+	MaxPDFAge    time.Duration
+}
+
+// profiles are only used for the fields that no configuration source sets
+var profiles = map[string]Profile{
+	ModeProd: {
+		FrontendURL:  "https://uspy.me",
+		CookieDomain: "uspy.me",
+		MaxPDFAge:    time.Hour,
+	},
+	ModeDev: {
+		FrontendURL:  "https://frontdev.uspy.me",
+		CookieDomain: "uspy.me",
+		MaxPDFAge:    30 * 24 * time.Hour, // transcripts are reused for testing
+	},
+	ModeLocal: {
+		FrontendURL:  "http://127.0.0.1",
+		CookieDomain: "127.0.0.1",
+		MaxPDFAge:    time.Hour,
+	},
+}
+
+// applyProfile fills the empty fields with the profile of the mode
+func (c *Config) applyProfile() {
+	profile := profiles[c.Mode] // unknown modes are reported by Validate
+
+	if c.FrontendURL == "" {
+		c.FrontendURL = profile.FrontendURL
+	}
+	c.FrontendURL = strings.TrimSuffix(c.FrontendURL, "/")
+
+	if len(c.AllowedOrigins) == 0 {
+		c.AllowedOrigins = []string{c.FrontendURL}
+	}
+
+	if c.CookieDomain == "" {
+		c.CookieDomain = profile.CookieDomain
+	}
+
+	if c.MaxPDFAge == 0 {
+		c.MaxPDFAge = profile.MaxPDFAge
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Review category types
+const (
+	CategoryBool    = "bool"    // true or false
+	CategoryOrdinal = "ordinal" // integer between Min and Max, e.g. a 1-5 scale
+	CategoryRange   = "range"   // any number between Min and Max, bucketed by Step in histograms
+)
+
+// ReviewCategory describes one of the questions a user answers when reviewing a subject
+type ReviewCategory struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Min      float64 `json:"min,omitempty"`
+	Max      float64 `json:"max,omitempty"`
+	Step     float64 `json:"step,omitempty"`
+	Required bool    `json:"required"`
+}
+
+// DefaultReviewCategories are used when no review categories file is given
+//
+// worth_it is the original (and only) category, so it is the only one required for older clients to keep working
+var DefaultReviewCategories = []ReviewCategory{
+	{Name: "worth_it", Type: CategoryBool, Required: true},
+	{Name: "difficulty", Type: CategoryOrdinal, Min: 1, Max: 5},
+	{Name: "teaching", Type: CategoryOrdinal, Min: 1, Max: 5},
+	{Name: "workload", Type: CategoryRange, Min: 0, Max: 40, Step: 5}, // hours per week
+}
+
+type Reviews struct {
+	CategoriesPath string `envconfig:"USPY_REVIEW_CATEGORIES"` // JSON file with a list of review categories
+
+	categories []ReviewCategory
+}
+
+// Setup loads the review categories from the categories file, if there is one
+func (r *Reviews) Setup() error {
+	if r.CategoriesPath == "" {
+		r.categories = DefaultReviewCategories
+		return nil
+	}
+
+	data, err := os.ReadFile(r.CategoriesPath)
+	if err != nil {
+		return fmt.Errorf("could not read review categories file: %s", err.Error())
+	}
+
+	var categories []ReviewCategory
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return fmt.Errorf("could not parse review categories file: %s", err.Error())
+	}
+
+	if err := validateCategories(categories); err != nil {
+		return err
+	}
+
+	r.categories = categories
+	return nil
+}
+
+// Categories returns the configured review categories
+func (r Reviews) Categories() []ReviewCategory {
+	if r.categories == nil {
+		return DefaultReviewCategories
+	}
+
+	return r.categories
+}
+
+// Category looks up a review category by name
+func (r Reviews) Category(name string) (ReviewCategory, bool) {
+	for _, c := range r.Categories() {
+		if c.Name == name {
+			return c, true
+		}
+	}
+
+	return ReviewCategory{}, false
+}
+
+func validateCategories(categories []ReviewCategory) error {
+	if len(categories) == 0 {
+		return fmt.Errorf("at least one review category must be defined")
+	}
+
+	names := make(map[string]bool)
+	for _, c := range categories {
+		if c.Name == "" || c.Name == "total" {
+			return fmt.Errorf("invalid review category name: %q", c.Name)
+		} else if names[c.Name] {
+			return fmt.Errorf("review category %s is defined more than once", c.Name)
+		}
+		names[c.Name] = true
+
+		switch c.Type {
+		case CategoryBool:
+		case CategoryOrdinal:
+			if c.Min >= c.Max {
+				return fmt.Errorf("review category %s must have min < max", c.Name)
+			}
+		case CategoryRange:
+			if c.Min >= c.Max || c.Step <= 0 {
+				return fmt.Errorf("review category %s must have min < max and a positive step", c.Name)
+			}
+		default:
+			return fmt.Errorf("review category %s has unknown type %q", c.Name, c.Type)
+		}
+	}
+
+	return nil
+}
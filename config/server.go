@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// HTTPServer configures the timeouts of the http.Server and how long shutdown waits for in-flight requests
+type HTTPServer struct {
+	ReadTimeout     time.Duration `envconfig:"USPY_READ_TIMEOUT" default:"10s"`
+	WriteTimeout    time.Duration `envconfig:"USPY_WRITE_TIMEOUT" default:"30s"` // must be longer than USPY_REQUEST_TIMEOUT
+	IdleTimeout     time.Duration `envconfig:"USPY_IDLE_TIMEOUT" default:"120s"`
+	ShutdownTimeout time.Duration `envconfig:"USPY_SHUTDOWN_TIMEOUT" default:"8s"` // cloud run kills instances 10s after SIGTERM
+}
+
+// Setup checks that timeouts are not negative
+func (s HTTPServer) Setup() error {
+	for name, d := range map[string]time.Duration{
+		"read":     s.ReadTimeout,
+		"write":    s.WriteTimeout,
+		"idle":     s.IdleTimeout,
+		"shutdown": s.ShutdownTimeout,
+	} {
+		if d < 0 {
+			return fmt.Errorf("%s timeout must not be negative, got %s", name, d)
+		}
+	}
+
+	return nil
+}
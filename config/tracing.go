@@ -0,0 +1,29 @@
+package config
+
+import "fmt"
+
+// Trace exporters
+const (
+	TraceNone   = "none"   // spans are not recorded, incoming trace context is still propagated
+	TraceStdout = "stdout" // spans are written to stdout, for local debugging
+	TraceOTLP   = "otlp"   // spans are sent over OTLP/HTTP, see the OTEL_EXPORTER_OTLP_* variables
+)
+
+// Tracing configures OpenTelemetry tracing
+type Tracing struct {
+	TraceExporter    string  `envconfig:"USPY_TRACE_EXPORTER" default:"none"`
+	TraceSampleRatio float64 `envconfig:"USPY_TRACE_SAMPLE_RATIO" default:"1"` // of traces started by this server
+}
+
+// Setup checks the exporter and sample ratio
+func (t Tracing) Setup() error {
+	if t.TraceExporter != TraceNone && t.TraceExporter != TraceStdout && t.TraceExporter != TraceOTLP {
+		return fmt.Errorf("unknown trace exporter %q", t.TraceExporter)
+	}
+
+	if t.TraceSampleRatio < 0 || t.TraceSampleRatio > 1 {
+		return fmt.Errorf("trace sample ratio must be between 0 and 1, got %v", t.TraceSampleRatio)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Insecure defaults, so the server runs without any configuration outside production
+//
+// They must match the default tags of Config
+const (
+	DefaultJWTSecret = "my_secret"
+	DefaultAESKey    = "71deb5a48500599862d9e2170a60f90194a49fa81c24eacfe9da15cb76ba8b11"
+)
+
+// Validate checks the fields that are not checked by the Setup of their sub-configuration, reporting every problem at once
+//
+// In production it also refuses the default secrets and front-end URLs that are not https
+func (c Config) Validate() error {
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if _, ok := profiles[c.Mode]; !ok {
+		report("USPY_MODE must be one of %s, %s or %s, got %q", ModeProd, ModeDev, ModeLocal, c.Mode)
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		report("USPY_PORT must be a port number, got %q", c.Port)
+	}
+
+	if c.JWTSecret == "" {
+		report("USPY_JWT_SECRET must not be empty")
+	}
+
+	if key, err := hex.DecodeString(c.AESKey); err != nil || (len(key) != 16 && len(key) != 24 && len(key) != 32) {
+		report("USPY_AES_KEY must be a hex encoded 128, 192 or 256 bit key")
+	}
+
+	if err := validateOrigin(c.FrontendURL); err != nil {
+		report("USPY_FRONTEND_URL %s", err.Error())
+	}
+
+	for _, origin := range c.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			report("USPY_ALLOWED_ORIGINS %s", err.Error())
+		}
+	}
+
+	if c.CookieDomain == "" {
+		report("USPY_COOKIE_DOMAIN must not be empty")
+	}
+
+	if _, err := mail.ParseAddress(c.Sender); err != nil {
+		report("USPY_MAIL_SENDER must be an email address, got %q", c.Sender)
+	}
+
+	if c.MaxPDFAge <= 0 {
+		report("USPY_MAX_PDF_AGE must be positive, got %s", c.MaxPDFAge)
+	}
+
+	if c.RequestTimeout > 0 && c.WriteTimeout > 0 && c.WriteTimeout <= c.RequestTimeout {
+		report("USPY_WRITE_TIMEOUT (%s) must be longer than USPY_REQUEST_TIMEOUT (%s), or timed out requests get no response", c.WriteTimeout, c.RequestTimeout)
+	}
+
+	if c.Mode == ModeProd {
+		if c.JWTSecret == DefaultJWTSecret {
+			report("USPY_JWT_SECRET must be set in production, the default is public")
+		}
+
+		if c.AESKey == DefaultAESKey {
+			report("USPY_AES_KEY must be set in production, the default is public")
+		}
+
+		if u, err := url.Parse(c.FrontendURL); err == nil && u.Scheme != "https" {
+			report("USPY_FRONTEND_URL must use https in production, got %q", c.FrontendURL)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// validateOrigin checks that value is a scheme and a host, as in the Origin header
+func validateOrigin(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("must be a URL, got %q", value)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL with a host, got %q", value)
+	}
+
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("must not have a path, got %q", value)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"github.com/Projeto-USPY/uspy-backend/cache"
+	"github.com/Projeto-USPY/uspy-backend/config"
+)
+
+// CacheCollection stores the entries of the shared catalog cache
+const CacheCollection = "cache"
+
+// NewCache creates the catalog cache configured in config.Env.CatalogCache
+//
+// It returns nil, which caches nothing, if the TTL is 0
+func NewCache(DB Env) *cache.Cache {
+	conf := config.Env.CatalogCache
+	if conf.TTL <= 0 {
+		return nil
+	}
+
+	var backend cache.Backend
+	if conf.Backend == config.CacheFirestore {
+		backend = cache.FirestoreBackend{Collection: DB.Client.Collection(CacheCollection)}
+	}
+
+	return cache.New(conf.TTL, backend)
+}
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"golang.org/x/net/context"
+)
+
+// DefaultShards is the number of shards of a counter, each one takes about one write per second
+const DefaultShards = 10
+
+// ShardedCounter is a group of numeric fields whose increments are spread across several shard documents
+//
+// Firestore only sustains about one write per second to the same document, so fields that are incremented by many
+// users at once (such as review stats or comment votes) are split into shards. Each increment goes to a random shard
+// and the value is the sum of all shards, calculated when it is read.
+//
+// Shards are stored as {Collection}/{Key}-{i}, with the counter key and its values, so a collection can hold the shards
+// of many counters and they can all be read at once with ReadCounters
+type ShardedCounter struct {
+	Collection *firestore.CollectionRef
+	Key        string
+	Shards     int
+}
+
+// NewShardedCounter returns a counter with DefaultShards shards
+func NewShardedCounter(collection *firestore.CollectionRef, key string) ShardedCounter {
+	return ShardedCounter{Collection: collection, Key: key, Shards: DefaultShards}
+}
+
+// ShardRefs returns the references to every shard of the counter, some of which may not exist
+func (c ShardedCounter) ShardRefs() []*firestore.DocumentRef {
+	refs := make([]*firestore.DocumentRef, 0, c.Shards)
+	for i := 0; i < c.Shards; i++ {
+		refs = append(refs, c.Collection.Doc(fmt.Sprintf("%s-%d", c.Key, i)))
+	}
+
+	return refs
+}
+
+// IncrementData returns a random shard and the data that must be merged into it to apply the updates
+//
+// Update values must be firestore.Increment transforms. The data must be written with firestore.MergeAll
+func (c ShardedCounter) IncrementData(updates []firestore.Update) (*firestore.DocumentRef, map[string]interface{}) {
+	values := make(map[string]interface{})
+	for _, u := range updates {
+		path := u.FieldPath
+		if path == nil {
+			path = strings.Split(u.Path, ".")
+		}
+
+		node := values
+		for _, p := range path[:len(path)-1] {
+			next, ok := node[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				node[p] = next
+			}
+
+			node = next
+		}
+
+		node[path[len(path)-1]] = u.Value
+	}
+
+	shards := c.Shards
+	if shards <= 0 {
+		shards = 1
+	}
+
+	ref := c.Collection.Doc(fmt.Sprintf("%s-%d", c.Key, rand.Intn(shards)))
+	return ref, map[string]interface{}{"counter": c.Key, "values": values}
+}
+
+// Increment applies the updates to a random shard of the counter inside a transaction
+func (c ShardedCounter) Increment(tx *firestore.Transaction, updates []firestore.Update) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ref, data := c.IncrementData(updates)
+	return tx.Set(ref, data, firestore.MergeAll)
+}
+
+// Read sums every shard of the counter
+func (c ShardedCounter) Read(ctx context.Context) (map[string]interface{}, error) {
+	snaps, err := c.Collection.Where("counter", "==", c.Key).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	for _, s := range snaps {
+		if v, ok := s.Data()["values"].(map[string]interface{}); ok {
+			addValues(values, v)
+		}
+	}
+
+	return values, nil
+}
+
+// ReadCounters sums the shards of every counter stored in the collection, keyed by counter key
+func ReadCounters(ctx context.Context, collection *firestore.CollectionRef) (map[string]map[string]interface{}, error) {
+	snaps, err := collection.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]map[string]interface{})
+	for _, s := range snaps {
+		data := s.Data()
+		key, _ := data["counter"].(string)
+		values, ok := data["values"].(map[string]interface{})
+		if key == "" || !ok {
+			continue
+		}
+
+		if counters[key] == nil {
+			counters[key] = make(map[string]interface{})
+		}
+
+		addValues(counters[key], values)
+	}
+
+	return counters, nil
+}
+
+// CounterInt returns the integer at the given path of counter values, or 0 if there is none
+func CounterInt(values map[string]interface{}, path ...string) int {
+	switch v := lookup(values, path).(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+
+	return 0
+}
+
+// CounterFloat returns the number at the given path of counter values, or 0 if there is none
+func CounterFloat(values map[string]interface{}, path ...string) float64 {
+	switch v := lookup(values, path).(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+
+	return 0
+}
+
+// CounterMap returns the nested values at the given path of counter values, or nil if there are none
+func CounterMap(values map[string]interface{}, path ...string) map[string]interface{} {
+	m, _ := lookup(values, path).(map[string]interface{})
+	return m
+}
+
+func lookup(values map[string]interface{}, path []string) interface{} {
+	var current interface{} = values
+	for _, p := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		current = m[p]
+	}
+
+	return current
+}
+
+// addValues adds every number in src to dst, recursively
+//
+// Integers stay integers unless they are added to a float
+func addValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			next, ok := dst[k].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				dst[k] = next
+			}
+
+			addValues(next, value)
+		case int64:
+			switch current := dst[k].(type) {
+			case float64:
+				dst[k] = current + float64(value)
+			case int64:
+				dst[k] = current + value
+			default:
+				dst[k] = value
+			}
+		case float64:
+			switch current := dst[k].(type) {
+			case float64:
+				dst[k] = current + value
+			case int64:
+				dst[k] = float64(current) + value
+			default:
+				dst[k] = value
+			}
+		}
+	}
+}
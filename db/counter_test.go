@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddValues(t *testing.T) {
+	sum := make(map[string]interface{})
+	addValues(sum, map[string]interface{}{
+		"total":      int64(2),
+		"categories": map[string]interface{}{"difficulty": map[string]interface{}{"sum": float64(7)}},
+	})
+	addValues(sum, map[string]interface{}{
+		"total":      int64(-1),
+		"categories": map[string]interface{}{"difficulty": map[string]interface{}{"sum": int64(-3)}},
+	})
+
+	assert.Equal(t, 1, CounterInt(sum, "total"))
+	assert.Equal(t, 4.0, CounterFloat(sum, "categories", "difficulty", "sum"))
+	assert.Len(t, CounterMap(sum, "categories"), 1)
+
+	assert.Equal(t, 0, CounterInt(sum, "missing"))
+	assert.Equal(t, 0, CounterInt(sum, "total", "not", "a", "map"))
+	assert.Nil(t, CounterMap(sum, "total"))
+}
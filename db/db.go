@@ -2,11 +2,13 @@
 package db
 
 import (
-	"log"
-
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go"
+	"github.com/Projeto-USPY/uspy-backend/cache"
 	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/Projeto-USPY/uspy-backend/metrics"
+	"github.com/Projeto-USPY/uspy-backend/tracing"
 	"golang.org/x/net/context"
 	"google.golang.org/api/option"
 )
@@ -39,29 +41,17 @@ type Object struct {
 type Env struct {
 	Client *firestore.Client
 	Ctx    context.Context
-}
-
-// Env.Restore restores a document with a specific HashID and collection origin from Firestore
-// collection cannot end in "/"
-func (db Env) Restore(collection, HashID string) (*firestore.DocumentSnapshot, error) {
-	snap, err := db.Client.Collection(collection).Doc(HashID).Get(db.Ctx)
-	if err != nil {
-		return nil, err
-	}
 
-	return snap, nil
+	// Cache holds catalog data, it is nil (caching nothing) unless set by the server
+	Cache *cache.Cache
 }
 
-// Env.RestoreCollection is similar to Env.Restore, but restores all documents from a collection
+// Env.WithContext returns a copy of the environment whose operations use the given context
 //
-// Collection cannot end in "/"
-func (db Env) RestoreCollection(collection string) ([]*firestore.DocumentSnapshot, error) {
-	snap, err := db.Client.Collection(collection).Documents(db.Ctx).GetAll()
-	if err != nil {
-		return nil, err
-	}
-
-	return snap, nil
+// Request handlers should use the request's context, so operations are cancelled when the client disconnects or the request times out
+func (db Env) WithContext(ctx context.Context) Env {
+	db.Ctx = ctx
+	return db
 }
 
 // Env.Insert inserts an entity that implements Inserter into a DB collection
@@ -95,29 +85,34 @@ func InitFireStore() Env {
 		Ctx: context.Background(),
 	}
 
+	// Firestore operations are traced and measured, spans are started first so metrics are taken inside them
+	opts := make([]option.ClientOption, 0, 4)
+	for _, dialOpt := range append(tracing.FirestoreDialOptions(), metrics.FirestoreDialOptions()...) {
+		opts = append(opts, option.WithGRPCDialOption(dialOpt))
+	}
+
 	if config.Env.IsUsingProjectID() {
 		conf := &firebase.Config{ProjectID: config.Env.Identify()}
-		app, err := firebase.NewApp(DB.Ctx, conf)
+		app, err := firebase.NewApp(DB.Ctx, conf, opts...)
 		if err != nil {
-			log.Fatalln(err)
+			logger.Fatal("could not initialize firebase", logger.Fields{"error": err})
 		}
 
 		DB.Client, err = app.Firestore(DB.Ctx)
 		if err != nil {
-			log.Fatalln(err)
+			logger.Fatal("could not initialize firestore", logger.Fields{"error": err})
 		}
 	} else {
 		sa := option.WithCredentialsFile(config.Env.Identify())
 
-		app, err := firebase.NewApp(DB.Ctx, nil, sa)
+		app, err := firebase.NewApp(DB.Ctx, nil, append(opts, sa)...)
 		if err != nil {
-			log.Fatalln(err)
+			logger.Fatal("could not initialize firebase", logger.Fields{"error": err})
 		}
 
 		DB.Client, err = app.Firestore(DB.Ctx)
 		if err != nil {
-			log.Println(err)
-			log.Fatalln("There might be something wrong with your credentials file!")
+			logger.Fatal("could not initialize firestore, there might be something wrong with your credentials file", logger.Fields{"error": err})
 		}
 	}
 
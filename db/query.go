@@ -0,0 +1,145 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotFound is returned by Get when the document does not exist
+var ErrNotFound = errors.New("document not found")
+
+// Filter is a where clause, see firestore.Query.Where for the valid operators
+type Filter struct {
+	Path  string
+	Op    string
+	Value interface{}
+}
+
+// Order sorts query results by a field
+type Order struct {
+	Path      string
+	Direction firestore.Direction
+}
+
+// Query describes which documents of a collection are listed
+//
+// The zero value lists every document
+type Query struct {
+	Where   []Filter
+	OrderBy []Order
+	Limit   int
+	Offset  int
+
+	// StartAfter is a cursor: results start after the document with these values of the OrderBy fields
+	StartAfter []interface{}
+}
+
+// build applies the query to a collection
+func (q Query) build(col *firestore.CollectionRef) firestore.Query {
+	query := col.Query
+	for _, f := range q.Where {
+		query = query.Where(f.Path, f.Op, f.Value)
+	}
+
+	for _, o := range q.OrderBy {
+		query = query.OrderBy(o.Path, o.Direction)
+	}
+
+	if len(q.StartAfter) > 0 {
+		query = query.StartAfter(q.StartAfter...)
+	}
+
+	if q.Offset > 0 {
+		query = query.Offset(q.Offset)
+	}
+
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+
+	return query
+}
+
+// Get fetches the document with the given ID from a collection
+//
+// If it does not exist, the error wraps ErrNotFound. Collection cannot end in "/"
+func Get[T any](DB Env, collection, id string) (*T, error) {
+	snap, err := DB.Client.Collection(collection).Doc(id).Get(DB.Ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, collection, id)
+		}
+
+		return nil, err
+	}
+
+	var value T
+	if err := snap.DataTo(&value); err != nil {
+		return nil, fmt.Errorf("could not bind %s: %s", snap.Ref.Path, err.Error())
+	}
+
+	return &value, nil
+}
+
+// Exists reports whether the document with the given ID exists in a collection
+func Exists(DB Env, collection, id string) (bool, error) {
+	_, err := DB.Client.Collection(collection).Doc(id).Get(DB.Ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Each streams the documents of a collection that match the query, calling f for each one
+//
+// Iteration stops at the first error returned by f, which is returned as is
+func Each[T any](DB Env, collection string, q Query, f func(ref *firestore.DocumentRef, value *T) error) error {
+	iter := q.build(DB.Client.Collection(collection)).Documents(DB.Ctx)
+	defer iter.Stop()
+
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var value T
+		if err := snap.DataTo(&value); err != nil {
+			return fmt.Errorf("could not bind %s: %s", snap.Ref.Path, err.Error())
+		}
+
+		if err := f(snap.Ref, &value); err != nil {
+			return err
+		}
+	}
+}
+
+// List fetches the documents of a collection that match the query
+func List[T any](DB Env, collection string, q Query) ([]T, error) {
+	values := make([]T, 0)
+	err := Each(DB, collection, q, func(_ *firestore.DocumentRef, value *T) error {
+		values = append(values, *value)
+		return nil
+	})
+
+	return values, err
+}
+
+// ListByID is like List, but the documents are keyed by their IDs
+func ListByID[T any](DB Env, collection string, q Query) (map[string]*T, error) {
+	values := make(map[string]*T)
+	err := Each(DB, collection, q, func(ref *firestore.DocumentRef, value *T) error {
+		values[ref.ID] = value
+		return nil
+	})
+
+	return values, err
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+func TestQueryBuild(t *testing.T) {
+	// the client is never connected, queries are only built
+	client, err := firestore.NewClient(
+		context.Background(),
+		"test",
+		option.WithoutAuthentication(),
+		option.WithEndpoint("localhost:0"),
+		option.WithGRPCDialOption(grpc.WithInsecure()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	col := client.Collection("subjects")
+	assert.Equal(t, col.Query, Query{}.build(col), "the zero value lists every document")
+
+	q := Query{
+		Where:      []Filter{{Path: "course", Op: "==", Value: "55041"}},
+		OrderBy:    []Order{{Path: "semester", Direction: firestore.Desc}},
+		Limit:      10,
+		Offset:     5,
+		StartAfter: []interface{}{4},
+	}
+
+	expected := col.Where("course", "==", "55041").OrderBy("semester", firestore.Desc).StartAfter(4).Offset(5).Limit(10)
+	assert.Equal(t, expected, q.build(col))
+}
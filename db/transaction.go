@@ -0,0 +1,26 @@
+package db
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RunTransaction runs f in a Firestore transaction, traced as one span named after the operation
+//
+// f is run again if the transaction is retried, the span records how many attempts were made
+func RunTransaction(DB Env, operation string, f func(context.Context, *firestore.Transaction) error) error {
+	ctx, span := tracing.Start(DB.Ctx, "firestore.transaction "+operation)
+
+	attempts := 0
+	err := DB.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		attempts++
+		return f(ctx, tx)
+	})
+
+	span.SetAttributes(attribute.Int("firestore.transaction.attempts", attempts))
+	tracing.End(span, err)
+	return err
+}
@@ -0,0 +1,92 @@
+package db_utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Projeto-USPY/uspy-backend/cache"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+)
+
+// Catalog cache keys, subject keys are followed by the subject hash and course subject keys by the course code and specialization
+const (
+	coursesKey        = "courses"
+	subjectKey        = "subject/"
+	courseSubjectsKey = "course_subjects/"
+)
+
+// GetCourses fetches every course, it is cached
+func GetCourses(DB db.Env) ([]models.Course, cache.Meta, error) {
+	return cache.Load(DB.Ctx, DB.Cache, coursesKey, func() ([]models.Course, error) {
+		return db.List[models.Course](DB, "courses", db.Query{})
+	})
+}
+
+// GetSubject fetches the subject with the given hash, it is cached
+//
+// If it does not exist, the error wraps db.ErrNotFound. The stats in the cached document do not include its sharded counter
+func GetSubject(DB db.Env, subHash string) (*models.Subject, error) {
+	sub, _, err := cache.Load(DB.Ctx, DB.Cache, subjectKey+subHash, func() (models.Subject, error) {
+		sub, err := db.Get[models.Subject](DB, "subjects", subHash)
+		if err != nil {
+			return models.Subject{}, err
+		}
+
+		sub.Stats.Normalize()
+		return *sub, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the stats are usually added to, so the cached maps must not be shared
+	sub.Stats = sub.Stats.Clone()
+	return &sub, nil
+}
+
+// InvalidateSubjects removes the given subjects, and every course subject list, from the catalog cache
+func InvalidateSubjects(ctx context.Context, c *cache.Cache, subHashes []string) {
+	for _, hash := range subHashes {
+		if err := c.Invalidate(ctx, subjectKey+hash); err != nil {
+			logger.FromContext(ctx).Warn("could not invalidate cached subject", logger.Fields{"subject": hash, "error": err})
+		}
+	}
+
+	if err := c.Invalidate(ctx, courseSubjectsKey); err != nil {
+		logger.FromContext(ctx).Warn("could not invalidate cached course subjects", logger.Fields{"error": err})
+	}
+}
+
+// WatchCourses invalidates the cached courses whenever the courses collection changes
+//
+// It blocks until ctx is cancelled, so it should be run in its own goroutine
+func WatchCourses(ctx context.Context, DB db.Env) {
+	it := DB.Client.Collection("courses").Snapshots(ctx)
+	defer it.Stop()
+
+	for first := true; ; first = false {
+		snap, err := it.Next()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("stopped watching courses for catalog cache", logger.Fields{"error": err})
+			}
+			return
+		}
+
+		// the first snapshot has every course, it says nothing about changes
+		if first || len(snap.Changes) == 0 {
+			continue
+		}
+
+		if err := DB.Cache.Invalidate(ctx, coursesKey); err != nil {
+			logger.Warn("could not invalidate cached courses", logger.Fields{"error": err})
+		}
+	}
+}
+
+func courseSubjectsCacheKey(course, specialization string) string {
+	return fmt.Sprintf("%s%s/%s", courseSubjectsKey, course, specialization)
+}
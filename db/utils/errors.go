@@ -6,4 +6,5 @@ var (
 	ErrSubjectNotFound = errors.New("subject does not exist")
 	ErrNoPermission    = errors.New("user has not done subject")
 	ErrCommentNotFound = errors.New("comment not found")
+	ErrMajorNotFound   = errors.New("user is not enrolled in major")
 )
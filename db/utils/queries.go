@@ -0,0 +1,194 @@
+package db_utils
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/cache"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+)
+
+// GetCourseSubjects fetches every subject that belongs to the given course and specialization, it is cached
+//
+// The subjects are shared with the cache and must not be modified
+func GetCourseSubjects(DB db.Env, course, specialization string) ([]models.Subject, error) {
+	subjects, _, err := cache.Load(DB.Ctx, DB.Cache, courseSubjectsCacheKey(course, specialization), func() ([]models.Subject, error) {
+		return db.List[models.Subject](DB, "subjects", db.Query{Where: []db.Filter{
+			{Path: "course", Op: "==", Value: course},
+			{Path: "specialization", Op: "==", Value: specialization},
+		}})
+	})
+
+	return subjects, err
+}
+
+// GetUserRecords fetches the user's records for each of the given subjects, keyed by subject code
+//
+// Subjects the user has never taken are not present in the resulting map
+func GetUserRecords(DB db.Env, userHash string, subjects []models.Subject) (map[string][]models.Record, error) {
+	codes := make(map[string]string, len(subjects)) // subject hash -> subject code
+	for _, s := range subjects {
+		codes[s.Hash()] = s.Code
+	}
+
+	// final scores documents are never written, only their records, but their references can still be listed
+	refs, err := DB.Client.Collection("users/" + userHash + "/final_scores").DocumentRefs(DB.Ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list final scores: %s", err.Error())
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		fetchErr error
+	)
+
+	records := make(map[string][]models.Record)
+	for _, ref := range refs {
+		code, ok := codes[ref.ID]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(code, subHash string) {
+			defer wg.Done()
+
+			recs, err := db.List[models.Record](DB, "users/"+userHash+"/final_scores/"+subHash+"/records", db.Query{})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				fetchErr = fmt.Errorf("failed to fetch records of %s: %s", code, err.Error())
+				return
+			}
+
+			for _, rec := range recs {
+				rec.Subject = code
+				records[code] = append(records[code], rec)
+			}
+		}(code, ref.ID)
+	}
+
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	return records, nil
+}
+
+// GetSubjectOfferings fetches every offering of the subject with the given hash, keyed by offering hash
+func GetSubjectOfferings(DB db.Env, subHash string) (map[string]*models.Offering, error) {
+	return db.ListByID[models.Offering](DB, "subjects/"+subHash+"/offerings", db.Query{})
+}
+
+// GetProfessorComments fetches a page of the comments made on every offering of the professor, most recent first
+//
+// Each offering is queried for at most limit+1 comments after the cursor, so the cost of a page does not grow with the
+// professor's history. Votes are only read for the comments in the page. The returned cursor is nil on the last page
+func GetProfessorComments(DB db.Env, prof *models.Professor, after *models.CommentCursor, limit int) ([]models.ProfessorComment, *models.CommentCursor, error) {
+	query := db.Query{
+		OrderBy: []db.Order{{Path: "last_update", Direction: firestore.Desc}, {Path: firestore.DocumentID, Direction: firestore.Desc}},
+		Limit:   limit + 1,
+	}
+
+	if after != nil {
+		query.StartAfter = []interface{}{after.Timestamp, after.Author}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		fetchErr error
+	)
+
+	comments := make([]models.ProfessorComment, 0)
+	for _, off := range prof.Offerings {
+		wg.Add(1)
+		go func(off models.ProfessorOffering) {
+			defer wg.Done()
+
+			path := fmt.Sprintf("subjects/%s/offerings/%s/comments", off.SubjectHash(), prof.Hash)
+			offComments := make([]models.ProfessorComment, 0, query.Limit)
+			err := db.Each(DB, path, query, func(ref *firestore.DocumentRef, c *models.Comment) error {
+				offComments = append(offComments, models.ProfessorComment{Offering: off, Author: ref.ID, Comment: c})
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				fetchErr = fmt.Errorf("failed to fetch comments of %s: %s", off.Subject, err.Error())
+				return
+			}
+
+			comments = append(comments, offComments...)
+		}(off)
+	}
+
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, nil, fetchErr
+	}
+
+	// the same order as the queries, ties between offerings (same author and instant) are too unlikely to matter
+	sort.Slice(comments, func(i, j int) bool {
+		cursor := models.CommentCursor{Timestamp: comments[j].Comment.Timestamp, Author: comments[j].Author}
+		return cursor.Before(comments[i].Comment.Timestamp, comments[i].Author)
+	})
+
+	var next *models.CommentCursor
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[limit-1]
+		next = &models.CommentCursor{Timestamp: last.Comment.Timestamp, Author: last.Author}
+	}
+
+	if err := addProfessorCommentVotes(DB, prof, comments); err != nil {
+		return nil, nil, err
+	}
+
+	return comments, next, nil
+}
+
+// addProfessorCommentVotes reads the votes counter of each comment concurrently
+func addProfessorCommentVotes(DB db.Env, prof *models.Professor, comments []models.ProfessorComment) error {
+	errs := make(chan error, len(comments))
+	for _, c := range comments {
+		go func(c models.ProfessorComment) {
+			votes, err := models.VotesCounter(DB, c.Offering.SubjectHash(), prof.Hash, c.Author).Read(DB.Ctx)
+			if err == nil {
+				c.Comment.AddVotes(votes)
+			}
+
+			errs <- err
+		}(c)
+	}
+
+	for range comments {
+		if err := <-errs; err != nil {
+			return fmt.Errorf("failed to read comment votes: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// GetProfessor fetches the professor with the given hash
+//
+// If the professor does not exist, the error wraps db.ErrNotFound
+func GetProfessor(DB db.Env, hash string) (*models.Professor, error) {
+	prof, err := db.Get[models.Professor](DB, "professors", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	prof.Hash = hash
+	return prof, nil
+}
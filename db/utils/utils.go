@@ -2,19 +2,20 @@ package db_utils
 
 import (
 	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
 )
 
 func checkSubjectExists(DB db.Env, subHash string) error {
-	snap, err := DB.Restore("subjects", subHash)
-	if snap == nil || !snap.Exists() {
+	exists, err := db.Exists(DB, "subjects", subHash)
+	if !exists {
 		return ErrSubjectNotFound
 	}
 	return err
 }
 
 func checkSubjectRecords(DB db.Env, userHash, subHash string) error {
-	col, err := DB.RestoreCollection("users/" + userHash + "/final_scores/" + subHash + "/records")
-	if len(col) == 0 {
+	records, err := db.List[models.Record](DB, "users/"+userHash+"/final_scores/"+subHash+"/records", db.Query{Limit: 1})
+	if len(records) == 0 {
 		return ErrNoPermission
 	}
 	return err
@@ -30,3 +31,15 @@ func CheckSubjectPermission(DB db.Env, userHash, subHash string) error {
 
 	return nil
 }
+
+// CheckUserMajor checks if the user is enrolled in the given major
+func CheckUserMajor(DB db.Env, userHash string, major models.Major) error {
+	exists, err := db.Exists(DB, "users/"+userHash+"/majors", major.Hash())
+	if err != nil {
+		return err
+	} else if !exists {
+		return ErrMajorNotFound
+	}
+
+	return nil
+}
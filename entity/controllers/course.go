@@ -0,0 +1,6 @@
+package controllers
+
+type Course struct {
+	Code           string `form:"course" binding:"required,alphanum"`
+	Specialization string `form:"specialization" binding:"required,alphanum"`
+}
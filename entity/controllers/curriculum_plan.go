@@ -0,0 +1,7 @@
+package controllers
+
+type CurriculumPlan struct {
+	Course
+	CreditCap    int `form:"credits" binding:"required,min=1,max=80"`
+	MaxSemesters int `form:"semesters" binding:"omitempty,min=1,max=30"`
+}
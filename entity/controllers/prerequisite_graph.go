@@ -0,0 +1,6 @@
+package controllers
+
+type PrerequisiteGraphOptions struct {
+	Format    string `form:"format" binding:"omitempty,oneof=json dot"`
+	Direction string `form:"direction" binding:"omitempty,oneof=predecessors successors both"`
+}
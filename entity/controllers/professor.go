@@ -0,0 +1,17 @@
+package controllers
+
+type Professor struct {
+	Hash string `form:"code" binding:"required,len=64,alphanum"` // sha256
+}
+
+type ProfessorSearch struct {
+	Query string `form:"q" binding:"required,min=3,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type ProfessorComments struct {
+	Professor
+
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"omitempty,max=200"` // next cursor of the previous page
+}
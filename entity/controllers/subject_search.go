@@ -0,0 +1,10 @@
+package controllers
+
+type SubjectSearch struct {
+	Query          string `form:"q" binding:"required,min=2,max=100"`
+	CourseCode     string `form:"course" binding:"omitempty,alphanum"`
+	Specialization string `form:"specialization" binding:"omitempty,alphanum"`
+	Optional       *bool  `form:"optional"`
+
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
 	"github.com/Projeto-USPY/uspy-backend/utils"
 	"github.com/google/uuid"
 )
@@ -23,3 +26,22 @@ type Comment struct {
 func (c Comment) Hash() string {
 	return utils.SHA256(c.User)
 }
+
+// VotesCounter returns the sharded counter that holds the votes of a comment
+//
+// The counters of every comment of an offering share the same collection, so they can be read at once with db.ReadCounters.
+// commentID is the comment document ID, which is the hash of its author
+func VotesCounter(DB db.Env, subHash, profHash, commentID string) db.ShardedCounter {
+	return db.NewShardedCounter(VotesCollection(DB, subHash, profHash), commentID)
+}
+
+// VotesCollection returns the collection with the votes counters of every comment of an offering
+func VotesCollection(DB db.Env, subHash, profHash string) *firestore.CollectionRef {
+	return DB.Client.Collection(fmt.Sprintf("subjects/%s/offerings/%s/comment_votes", subHash, profHash))
+}
+
+// AddVotes adds the values read from the votes counter to the comment
+func (c *Comment) AddVotes(values map[string]interface{}) {
+	c.Upvotes += db.CounterInt(values, "upvotes")
+	c.Downvotes += db.CounterInt(values, "downvotes")
+}
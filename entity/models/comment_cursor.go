@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a comment cursor was not made by CommentCursor.String
+var ErrInvalidCursor = errors.New("invalid comment cursor")
+
+// CommentCursor marks the last comment of a page, when comments are sorted by last update and then by author, most recent first
+//
+// Author is the comment document ID, which is the hash of its author
+type CommentCursor struct {
+	Timestamp time.Time
+	Author    string
+}
+
+// String encodes the cursor so it can be sent to clients, who must treat it as opaque
+func (c CommentCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + c.Author))
+}
+
+// ParseCommentCursor decodes a cursor encoded by CommentCursor.String
+func ParseCommentCursor(s string) (CommentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return CommentCursor{}, ErrInvalidCursor
+	}
+
+	fields := strings.SplitN(string(data), "|", 2)
+	if len(fields) != 2 || fields[1] == "" {
+		return CommentCursor{}, ErrInvalidCursor
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return CommentCursor{}, ErrInvalidCursor
+	}
+
+	return CommentCursor{Timestamp: timestamp, Author: fields[1]}, nil
+}
+
+// Before reports whether the comment written by author at timestamp comes before the cursor's in the sort order
+func (c CommentCursor) Before(timestamp time.Time, author string) bool {
+	if !timestamp.Equal(c.Timestamp) {
+		return timestamp.After(c.Timestamp)
+	}
+
+	return author > c.Author
+}
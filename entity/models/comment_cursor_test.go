@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentCursor(t *testing.T) {
+	cursor := CommentCursor{Timestamp: time.Date(2022, 3, 4, 5, 6, 7, 8000, time.UTC), Author: "abc"}
+
+	parsed, err := ParseCommentCursor(cursor.String())
+	require.NoError(t, err)
+	assert.True(t, cursor.Timestamp.Equal(parsed.Timestamp))
+	assert.Equal(t, cursor.Author, parsed.Author)
+
+	for _, invalid := range []string{"%%%", "bm9waXBl", "MjAyMi0wMy0wNHw"} {
+		_, err := ParseCommentCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, invalid)
+	}
+
+	assert.True(t, cursor.Before(cursor.Timestamp.Add(time.Second), "aaa"), "more recent comments come first")
+	assert.True(t, cursor.Before(cursor.Timestamp, "abd"), "then authors in descending order")
+	assert.False(t, cursor.Before(cursor.Timestamp, "abc"))
+	assert.False(t, cursor.Before(cursor.Timestamp.Add(-time.Second), "zzz"))
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentAddVotes(t *testing.T) {
+	c := Comment{Upvotes: 2, Downvotes: 1}
+	c.AddVotes(map[string]interface{}{"upvotes": int64(3)})
+	c.AddVotes(nil)
+
+	assert.Equal(t, 5, c.Upvotes)
+	assert.Equal(t, 1, c.Downvotes)
+}
@@ -0,0 +1,433 @@
+package models
+
+import (
+	"sort"
+)
+
+// Reasons that explain where a subject was placed in a curriculum plan (or why it could not be placed)
+const (
+	ReasonRecommendedSemester = "recommended_semester" // semester suggested by the course structure
+	ReasonRequirementApproved = "requirement_approved" // requirement was already passed
+	ReasonRequirementAttended = "requirement_attended" // weak requirement satisfied by a previous, failed attempt
+	ReasonRequirementPlanned  = "requirement_planned"  // requirement is planned for an earlier semester
+	ReasonRequiredBy          = "required_by"          // subject is not mandatory, but a planned subject requires it
+	ReasonCreditCap           = "credit_cap"           // subject was postponed because the semester was full
+
+	ReasonExceedsCreditCap   = "exceeds_credit_cap"  // subject alone has more credits than allowed per semester
+	ReasonMissingRequirement = "missing_requirement" // requirement is not part of the course and was never taken
+	ReasonRequirementCycle   = "requirement_cycle"   // subject is part of a requirement cycle
+	ReasonBlockedBy          = "blocked_by"          // subject depends on another subject that cannot be scheduled
+	ReasonSemesterLimit      = "semester_limit"      // plan ran out of semesters
+)
+
+// PlanReason explains a decision taken by the planner
+//
+// Subject and Semester are only set when the reason refers to another subject or to a specific semester
+type PlanReason struct {
+	Code     string
+	Subject  string
+	Semester int
+	Strong   bool
+}
+
+// PlannedSubject is a subject in a curriculum plan, along with the reasons for its placement
+type PlannedSubject struct {
+	Subject Subject
+	Credits int
+	Reasons []PlanReason
+}
+
+// PlannedSemester is a semester in a curriculum plan, numbered from 1 (the next semester)
+type PlannedSemester struct {
+	Index    int
+	Credits  int
+	Subjects []PlannedSubject
+}
+
+// CurriculumPlan is a suggested order to take the remaining mandatory subjects of a course
+type CurriculumPlan struct {
+	CreditCap   int
+	Semesters   []PlannedSemester
+	Unscheduled []PlannedSubject
+}
+
+// planner holds the state used to build a CurriculumPlan
+type planner struct {
+	subjects  map[string]Subject
+	records   map[string][]Record
+	creditCap int
+
+	chosen    map[string][]Requirement // requirement group chosen for each subject in the plan
+	reasons   map[string][]PlanReason
+	planned   map[string]int // subject code -> semester index
+	blocked   map[string]PlanReason
+	targets   []string
+	targetSet map[string]bool
+}
+
+func (p *planner) approved(code string) bool {
+	for _, r := range p.records[code] {
+		if r.Approved() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *planner) attended(code string) bool {
+	for _, r := range p.records[code] {
+		if r.Attended() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// satisfiedByRecords reports whether the requirement was met by the student's past records
+func (p *planner) satisfiedByRecords(r Requirement) (bool, string) {
+	if p.approved(r.Subject) {
+		return true, ReasonRequirementApproved
+	}
+
+	if !r.Strong && p.attended(r.Subject) {
+		return true, ReasonRequirementAttended
+	}
+
+	return false, ""
+}
+
+// chooseGroup picks the requirement group that needs the fewest additional subjects
+//
+// Groups are alternatives, so the subject can be taken once every requirement of any single group is met.
+// Groups with requirements that are not part of the course and were never taken cannot be satisfied.
+func (p *planner) chooseGroup(sub Subject) ([]Requirement, *PlanReason) {
+	if len(sub.Requirements) == 0 {
+		return []Requirement{}, nil
+	}
+
+	keys := make([]string, 0, len(sub.Requirements))
+	for k := range sub.Requirements {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var best []Requirement
+	var missing *PlanReason
+	bestCost := -1
+
+	for _, k := range keys {
+		cost, feasible := 0, true
+		for _, r := range sub.Requirements[k] {
+			if ok, _ := p.satisfiedByRecords(r); ok {
+				continue
+			}
+
+			if _, inCourse := p.subjects[r.Subject]; !inCourse {
+				feasible = false
+				if missing == nil {
+					missing = &PlanReason{Code: ReasonMissingRequirement, Subject: r.Subject, Strong: r.Strong}
+				}
+				break
+			}
+
+			if !p.targetSet[r.Subject] {
+				cost++
+			}
+		}
+
+		if feasible && (bestCost == -1 || cost < bestCost) {
+			best, bestCost = sub.Requirements[k], cost
+		}
+	}
+
+	if bestCost == -1 {
+		return nil, missing
+	}
+
+	return best, nil
+}
+
+// collectTargets finds the subjects to be planned: remaining mandatory subjects and whatever they require
+func (p *planner) collectTargets() {
+	queue := make([]string, 0)
+	for code, s := range p.subjects {
+		if !s.Optional && !p.approved(code) {
+			queue = append(queue, code)
+			p.targetSet[code] = true
+		}
+	}
+	sort.Strings(queue)
+
+	for len(queue) > 0 {
+		code := queue[0]
+		queue = queue[1:]
+		p.targets = append(p.targets, code)
+
+		sub := p.subjects[code]
+		if sub.Credits() > p.creditCap {
+			p.block(code, PlanReason{Code: ReasonExceedsCreditCap})
+		}
+
+		group, missing := p.chooseGroup(sub)
+		if missing != nil {
+			p.block(code, *missing)
+			continue
+		}
+
+		p.chosen[code] = group
+		for _, r := range group {
+			if ok, _ := p.satisfiedByRecords(r); ok || p.targetSet[r.Subject] {
+				continue
+			}
+
+			p.targetSet[r.Subject] = true
+			p.addReason(r.Subject, PlanReason{Code: ReasonRequiredBy, Subject: code, Strong: r.Strong})
+			queue = append(queue, r.Subject)
+		}
+	}
+}
+
+// heights calculates the longest chain of planned subjects that depend on each subject
+//
+// Subjects with longer chains are scheduled first, since delaying them delays graduation the most
+func (p *planner) heights() map[string]int {
+	dependents := make(map[string][]string)
+	for code, group := range p.chosen {
+		for _, r := range group {
+			dependents[r.Subject] = append(dependents[r.Subject], code)
+		}
+	}
+
+	heights := make(map[string]int)
+	visiting := make(map[string]bool)
+
+	var height func(code string) int
+	height = func(code string) int {
+		if h, ok := heights[code]; ok {
+			return h
+		}
+
+		if visiting[code] { // cycle, it will be reported later
+			return 0
+		}
+
+		visiting[code] = true
+		h := 0
+		for _, d := range dependents[code] {
+			if dh := height(d) + 1; dh > h {
+				h = dh
+			}
+		}
+		visiting[code] = false
+
+		heights[code] = h
+		return h
+	}
+
+	for _, code := range p.targets {
+		height(code)
+	}
+
+	return heights
+}
+
+// eligible reports whether all requirements of the subject are met before the given semester
+func (p *planner) eligible(code string, semester int) bool {
+	for _, r := range p.chosen[code] {
+		if ok, _ := p.satisfiedByRecords(r); ok {
+			continue
+		}
+
+		if s, ok := p.planned[r.Subject]; !ok || s >= semester {
+			return false
+		}
+	}
+
+	return true
+}
+
+// addReason records a reason for the placement of a subject, unless one with the same code was already recorded
+//
+// A subject postponed for several semesters is only reported once, for the first semester it did not fit in
+func (p *planner) addReason(code string, reason PlanReason) {
+	for _, r := range p.reasons[code] {
+		if r.Code == reason.Code {
+			return
+		}
+	}
+
+	p.reasons[code] = append(p.reasons[code], reason)
+}
+
+// block records why a subject cannot be scheduled, keeping the first reason found
+func (p *planner) block(code string, reason PlanReason) {
+	if _, ok := p.blocked[code]; !ok {
+		p.blocked[code] = reason
+	}
+}
+
+// explain lists the reasons for a subject to be placed in its semester
+func (p *planner) explain(code string) []PlanReason {
+	reasons := make([]PlanReason, 0)
+	if s := p.subjects[code].Semester; s > 0 {
+		reasons = append(reasons, PlanReason{Code: ReasonRecommendedSemester, Semester: s})
+	}
+
+	for _, r := range p.chosen[code] {
+		if ok, reason := p.satisfiedByRecords(r); ok {
+			reasons = append(reasons, PlanReason{Code: reason, Subject: r.Subject, Strong: r.Strong})
+		} else {
+			reasons = append(reasons, PlanReason{Code: ReasonRequirementPlanned, Subject: r.Subject, Semester: p.planned[r.Subject], Strong: r.Strong})
+		}
+	}
+
+	return append(reasons, p.reasons[code]...)
+}
+
+// explainUnscheduled finds why a subject left out of the plan could not be placed
+func (p *planner) explainUnscheduled(code string, cycles map[string]bool) PlanReason {
+	if reason, ok := p.blocked[code]; ok {
+		return reason
+	}
+
+	if cycles[code] {
+		return PlanReason{Code: ReasonRequirementCycle}
+	}
+
+	for _, r := range p.chosen[code] {
+		if ok, _ := p.satisfiedByRecords(r); ok {
+			continue
+		}
+
+		if _, ok := p.planned[r.Subject]; !ok {
+			return PlanReason{Code: ReasonBlockedBy, Subject: r.Subject, Strong: r.Strong}
+		}
+	}
+
+	return PlanReason{Code: ReasonSemesterLimit}
+}
+
+// NewCurriculumPlan suggests in which semester each remaining mandatory subject of a course should be taken
+//
+// subjects are all subjects of the course and records are the student's records keyed by subject code.
+// Strong requirements must have been passed in an earlier semester, while weak requirements are also satisfied by
+// a past attempt in which the student failed by grade. Each semester has at most creditCap credits.
+func NewCurriculumPlan(subjects []Subject, records map[string][]Record, creditCap, maxSemesters int) *CurriculumPlan {
+	p := planner{
+		subjects:  make(map[string]Subject, len(subjects)),
+		records:   records,
+		creditCap: creditCap,
+		chosen:    make(map[string][]Requirement),
+		reasons:   make(map[string][]PlanReason),
+		planned:   make(map[string]int),
+		blocked:   make(map[string]PlanReason),
+		targets:   make([]string, 0),
+		targetSet: make(map[string]bool),
+	}
+
+	for _, s := range subjects {
+		p.subjects[s.Code] = s
+	}
+
+	p.collectTargets()
+
+	heights := p.heights()
+	queue := make([]string, 0, len(p.targets))
+	for _, code := range p.targets {
+		if _, blocked := p.blocked[code]; !blocked {
+			queue = append(queue, code)
+		}
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := p.subjects[queue[i]], p.subjects[queue[j]]
+		if heights[a.Code] != heights[b.Code] {
+			return heights[a.Code] > heights[b.Code]
+		}
+
+		if a.Semester != b.Semester {
+			return a.Semester < b.Semester
+		}
+
+		return a.Code < b.Code
+	})
+
+	plan := CurriculumPlan{
+		CreditCap:   creditCap,
+		Semesters:   make([]PlannedSemester, 0),
+		Unscheduled: make([]PlannedSubject, 0),
+	}
+
+	for semester := 1; semester <= maxSemesters && len(queue) > 0; semester++ {
+		current := PlannedSemester{Index: semester, Subjects: make([]PlannedSubject, 0)}
+		remaining := make([]string, 0, len(queue))
+		chosen := make([]string, 0)
+
+		for _, code := range queue {
+			credits := p.subjects[code].Credits()
+			if !p.eligible(code, semester) {
+				remaining = append(remaining, code)
+				continue
+			}
+
+			if current.Credits+credits > creditCap {
+				p.addReason(code, PlanReason{Code: ReasonCreditCap, Semester: semester})
+				remaining = append(remaining, code)
+				continue
+			}
+
+			current.Credits += credits
+			chosen = append(chosen, code)
+		}
+
+		// nothing could be scheduled, so nothing will be in later semesters either
+		if len(chosen) == 0 {
+			break
+		}
+
+		for _, code := range chosen {
+			p.planned[code] = semester
+		}
+
+		for _, code := range chosen {
+			current.Subjects = append(current.Subjects, PlannedSubject{
+				Subject: p.subjects[code],
+				Credits: p.subjects[code].Credits(),
+				Reasons: p.explain(code),
+			})
+		}
+
+		plan.Semesters = append(plan.Semesters, current)
+		queue = remaining
+	}
+
+	// report everything that was left out
+	leftover := make([]Subject, 0)
+	for _, code := range p.targets {
+		if _, ok := p.planned[code]; !ok {
+			sub := p.subjects[code]
+			sub.Requirements = map[string][]Requirement{"0": p.chosen[code]}
+			leftover = append(leftover, sub)
+		}
+	}
+
+	cycles := make(map[string]bool)
+	for _, c := range NewPrerequisiteGraph(leftover).Cycles() {
+		for _, code := range c {
+			cycles[code] = true
+		}
+	}
+
+	sort.Slice(leftover, func(i, j int) bool { return leftover[i].Code < leftover[j].Code })
+	for _, sub := range leftover {
+		plan.Unscheduled = append(plan.Unscheduled, PlannedSubject{
+			Subject: p.subjects[sub.Code],
+			Credits: sub.Credits(),
+			Reasons: append(p.reasons[sub.Code], p.explainUnscheduled(sub.Code, cycles)),
+		})
+	}
+
+	return &plan
+}
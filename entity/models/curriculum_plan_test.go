@@ -0,0 +1,140 @@
+package models
+
+import (
+	"testing"
+)
+
+func planSubject(code string, semester, credits int, optional bool, reqs ...Requirement) Subject {
+	s := requires(code, reqs...)
+	s.Semester, s.ClassCredits, s.Optional = semester, credits, optional
+	return s
+}
+
+func findPlanned(plan *CurriculumPlan, code string) int {
+	for _, s := range plan.Semesters {
+		for _, sub := range s.Subjects {
+			if sub.Subject.Code == code {
+				return s.Index
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestCurriculumPlanRespectsRequirements(t *testing.T) {
+	subjects := []Subject{
+		planSubject("A", 1, 4, false),
+		planSubject("B", 2, 4, false, Requirement{Subject: "A", Strong: true}),
+		planSubject("C", 3, 4, false, Requirement{Subject: "B", Strong: false}),
+		planSubject("D", 1, 4, false),
+		planSubject("E", 5, 4, true),
+	}
+
+	plan := NewCurriculumPlan(subjects, map[string][]Record{}, 8, 10)
+
+	if a, b, c := findPlanned(plan, "A"), findPlanned(plan, "B"), findPlanned(plan, "C"); a != 1 || b != 2 || c != 3 {
+		t.Fatalf("requirement chain was not respected: A=%d B=%d C=%d", a, b, c)
+	}
+
+	if findPlanned(plan, "E") != 0 {
+		t.Fatal("optional subjects should not be planned")
+	}
+
+	for _, s := range plan.Semesters {
+		if s.Credits > 8 {
+			t.Fatalf("semester %d exceeds credit cap: %d", s.Index, s.Credits)
+		}
+	}
+}
+
+func TestCurriculumPlanUsesRecords(t *testing.T) {
+	subjects := []Subject{
+		planSubject("A", 1, 4, false),
+		planSubject("B", 2, 4, false, Requirement{Subject: "A", Strong: false}),
+		planSubject("C", 2, 4, false, Requirement{Subject: "A", Strong: true}),
+	}
+
+	records := map[string][]Record{"A": {{Grade: 3.0, Status: StatusFailedGrade}}}
+	plan := NewCurriculumPlan(subjects, records, 20, 10)
+
+	if findPlanned(plan, "B") != 1 {
+		t.Fatal("weak requirement should be satisfied by a failed attempt")
+	}
+
+	if findPlanned(plan, "A") != 1 || findPlanned(plan, "C") != 2 {
+		t.Fatal("strong requirement should only be satisfied by an approval")
+	}
+}
+
+func TestCurriculumPlanReportsUnscheduled(t *testing.T) {
+	subjects := []Subject{
+		planSubject("A", 1, 4, false, Requirement{Subject: "X", Strong: true}),
+		planSubject("B", 2, 4, false, Requirement{Subject: "A", Strong: true}),
+		planSubject("C", 1, 30, false),
+		planSubject("D", 1, 4, false, Requirement{Subject: "E", Strong: true}),
+		planSubject("E", 1, 4, false, Requirement{Subject: "D", Strong: true}),
+	}
+
+	plan := NewCurriculumPlan(subjects, map[string][]Record{}, 20, 10)
+
+	expected := map[string]string{
+		"A": ReasonMissingRequirement,
+		"B": ReasonBlockedBy,
+		"C": ReasonExceedsCreditCap,
+		"D": ReasonRequirementCycle,
+		"E": ReasonRequirementCycle,
+	}
+
+	if len(plan.Unscheduled) != len(expected) {
+		t.Fatalf("expected %d unscheduled subjects, got %d", len(expected), len(plan.Unscheduled))
+	}
+
+	for _, s := range plan.Unscheduled {
+		last := s.Reasons[len(s.Reasons)-1]
+		if last.Code != expected[s.Subject.Code] {
+			t.Fatalf("subject %s: expected reason %s, got %s", s.Subject.Code, expected[s.Subject.Code], last.Code)
+		}
+	}
+}
+
+func TestCurriculumPlanReasonsAreNotRepeated(t *testing.T) {
+	subjects := []Subject{
+		planSubject("A", 1, 8, false),
+		planSubject("B", 1, 8, false),
+		planSubject("C", 1, 8, false),
+		planSubject("D", 1, 30, false, Requirement{Subject: "X", Strong: true}),
+	}
+
+	plan := NewCurriculumPlan(subjects, map[string][]Record{}, 8, 10)
+
+	if findPlanned(plan, "C") != 3 {
+		t.Fatalf("expected C to be postponed to semester 3, got %d", findPlanned(plan, "C"))
+	}
+
+	for _, s := range plan.Semesters {
+		for _, sub := range s.Subjects {
+			count := 0
+			for _, r := range sub.Reasons {
+				if r.Code == ReasonCreditCap {
+					count++
+					if r.Semester != 1 {
+						t.Fatalf("subject %s: expected credit cap reason for semester 1, got %d", sub.Subject.Code, r.Semester)
+					}
+				}
+			}
+
+			if count > 1 {
+				t.Fatalf("subject %s: credit cap reason repeated %d times", sub.Subject.Code, count)
+			}
+		}
+	}
+
+	if len(plan.Unscheduled) != 1 {
+		t.Fatalf("expected 1 unscheduled subject, got %d", len(plan.Unscheduled))
+	}
+
+	if reasons := plan.Unscheduled[0].Reasons; reasons[len(reasons)-1].Code != ReasonExceedsCreditCap {
+		t.Fatalf("expected reason %s, got %s", ReasonExceedsCreditCap, reasons[len(reasons)-1].Code)
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import "sort"
+
+// CreditProgress counts credits done and remaining for a set of subjects
+type CreditProgress struct {
+	Done      int
+	Remaining int
+	Total     int
+}
+
+func (cp *CreditProgress) add(credits int, done bool) {
+	cp.Total += credits
+	if done {
+		cp.Done += credits
+	} else {
+		cp.Remaining += credits
+	}
+}
+
+// OptionalProgress counts the optional credits done by the student
+//
+// Courses do not record how many optional credits are required to graduate, so nothing is counted as remaining.
+// Offered is the sum of every optional subject offered by the course
+type OptionalProgress struct {
+	Done    int
+	Offered int
+}
+
+func (op *OptionalProgress) add(credits int, done bool) {
+	op.Offered += credits
+	if done {
+		op.Done += credits
+	}
+}
+
+// SemesterProgress describes how many of the mandatory subjects of a course semester were passed
+type SemesterProgress struct {
+	Semester int
+	Subjects int
+	Approved int
+	Credits  CreditProgress
+}
+
+// FailedSubject is a subject the student failed and has not passed since
+type FailedSubject struct {
+	Subject  Subject
+	Attempts []Record
+}
+
+// DegreeProgress summarizes how far a student is from completing a course
+type DegreeProgress struct {
+	Mandatory CreditProgress
+	Optional  OptionalProgress
+	Semesters []SemesterProgress
+	Failed    []FailedSubject
+}
+
+// NewDegreeProgress joins the subjects of a course with the student's records, keyed by subject code
+func NewDegreeProgress(subjects []Subject, records map[string][]Record) *DegreeProgress {
+	progress := DegreeProgress{
+		Semesters: make([]SemesterProgress, 0),
+		Failed:    make([]FailedSubject, 0),
+	}
+
+	semesters := make(map[int]*SemesterProgress)
+	for _, s := range subjects {
+		approved, failed := false, false
+		for _, r := range records[s.Code] {
+			approved = approved || r.Approved()
+			failed = failed || r.Failed()
+		}
+
+		if failed && !approved {
+			progress.Failed = append(progress.Failed, FailedSubject{Subject: s, Attempts: records[s.Code]})
+		}
+
+		if s.Optional {
+			progress.Optional.add(s.Credits(), approved)
+			continue
+		}
+
+		progress.Mandatory.add(s.Credits(), approved)
+
+		sp, ok := semesters[s.Semester]
+		if !ok {
+			sp = &SemesterProgress{Semester: s.Semester}
+			semesters[s.Semester] = sp
+		}
+
+		sp.Subjects++
+		if approved {
+			sp.Approved++
+		}
+		sp.Credits.add(s.Credits(), approved)
+	}
+
+	for _, sp := range semesters {
+		progress.Semesters = append(progress.Semesters, *sp)
+	}
+
+	sort.Slice(progress.Semesters, func(i, j int) bool {
+		return progress.Semesters[i].Semester < progress.Semesters[j].Semester
+	})
+
+	sort.Slice(progress.Failed, func(i, j int) bool {
+		return progress.Failed[i].Subject.Code < progress.Failed[j].Subject.Code
+	})
+
+	return &progress
+}
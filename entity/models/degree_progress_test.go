@@ -0,0 +1,36 @@
+package models
+
+import "testing"
+
+func TestDegreeProgress(t *testing.T) {
+	subjects := []Subject{
+		planSubject("A", 1, 4, false),
+		planSubject("B", 1, 2, false),
+		planSubject("C", 2, 4, false),
+		planSubject("D", 3, 4, true),
+	}
+
+	records := map[string][]Record{
+		"A": {{Grade: 3.0, Status: StatusFailedGrade}, {Grade: 7.0, Status: StatusApproved}},
+		"C": {{Grade: 2.0, Status: StatusFailedAbsence}},
+		"D": {{Grade: 8.0, Status: StatusApproved}},
+	}
+
+	progress := NewDegreeProgress(subjects, records)
+
+	if progress.Mandatory != (CreditProgress{Done: 4, Remaining: 6, Total: 10}) {
+		t.Fatalf("unexpected mandatory progress: %+v", progress.Mandatory)
+	}
+
+	if progress.Optional != (OptionalProgress{Done: 4, Offered: 4}) {
+		t.Fatalf("unexpected optional progress: %+v", progress.Optional)
+	}
+
+	if len(progress.Semesters) != 2 || progress.Semesters[0].Approved != 1 || progress.Semesters[0].Subjects != 2 {
+		t.Fatalf("unexpected semester progress: %+v", progress.Semesters)
+	}
+
+	if len(progress.Failed) != 1 || progress.Failed[0].Subject.Code != "C" {
+		t.Fatalf("only C should be failed and not retaken: %+v", progress.Failed)
+	}
+}
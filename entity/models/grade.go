@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+)
+
+// Grade is an anonymous final grade stored in subjects/{hash}/grades
+//
+// Only the grade itself is required, older grades do not have the year, semester or offering.
+// Offering is the offering hash (sha256 of the professor code), set only when the professor that taught the subject
+// in that year could be determined
+type Grade struct {
+	Value    float64 `firestore:"grade"`
+	Year     int     `firestore:"year,omitempty"`
+	Semester int     `firestore:"semester,omitempty"`
+	Offering string  `firestore:"offering,omitempty"`
+}
+
+// NewGradeFromRecord creates the anonymous grade for a user record
+//
+// offerings are the subject offerings keyed by hash and are used to find who taught the subject in the record's year
+func NewGradeFromRecord(rec Record, offerings map[string]*Offering) Grade {
+	return Grade{
+		Value:    rec.Grade,
+		Year:     rec.Year,
+		Semester: rec.Semester,
+		Offering: OfferingInYear(offerings, rec.Year),
+	}
+}
+
+// OfferingInYear returns the hash of the only offering that took place in the given year
+//
+// If no offering or more than one offering took place that year, the professor cannot be known and it returns an empty string
+func OfferingInYear(offerings map[string]*Offering, year int) string {
+	if year == 0 {
+		return ""
+	}
+
+	prefix := strconv.Itoa(year)
+	found := ""
+	for hash, off := range offerings {
+		for _, y := range off.Years {
+			if strings.HasPrefix(y, prefix) {
+				if found != "" {
+					return ""
+				}
+
+				found = hash
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+func (g Grade) Insert(DB db.Env, collection string) error {
+	_, _, err := DB.Client.Collection(collection).Add(DB.Ctx, g)
+	return err
+}
+
+func (g Grade) Update(DB db.Env, collection string) error { return nil }
+
+// Approved reports whether the grade is a passing grade
+func (g Grade) Approved() bool {
+	return g.Value >= 5.0
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GradeDistribution summarizes a set of grades
+//
+// Buckets are keyed by the grade rounded to one decimal place
+type GradeDistribution struct {
+	Count    int
+	Buckets  map[string]int
+	Average  float64
+	Approval float64
+}
+
+// NewGradeDistribution builds the distribution of the given grades
+func NewGradeDistribution(grades []Grade) GradeDistribution {
+	dist := GradeDistribution{Buckets: make(map[string]int)}
+
+	for _, g := range grades {
+		dist.Buckets[fmt.Sprintf("%.1f", g.Value)]++
+		dist.Average += g.Value
+		if g.Approved() {
+			dist.Approval++
+		}
+	}
+
+	if dist.Count = len(grades); dist.Count > 0 {
+		dist.Average /= float64(dist.Count)
+		dist.Approval /= float64(dist.Count)
+	}
+
+	return dist
+}
+
+// Period is a semester of a given year
+type Period struct {
+	Year     int
+	Semester int
+}
+
+// Before reports whether the period comes before the other one
+func (p Period) Before(other Period) bool {
+	if p.Year != other.Year {
+		return p.Year < other.Year
+	}
+
+	return p.Semester < other.Semester
+}
+
+// GradesByOffering groups grades by the offering they were given in
+//
+// Grades whose offering is unknown are grouped under the empty string
+func GradesByOffering(grades []Grade) map[string][]Grade {
+	groups := make(map[string][]Grade)
+	for _, g := range grades {
+		groups[g.Offering] = append(groups[g.Offering], g)
+	}
+
+	return groups
+}
+
+// GradesByYear groups grades by year, ignoring grades whose year is unknown
+func GradesByYear(grades []Grade) map[int][]Grade {
+	groups := make(map[int][]Grade)
+	for _, g := range grades {
+		if g.Year != 0 {
+			groups[g.Year] = append(groups[g.Year], g)
+		}
+	}
+
+	return groups
+}
+
+// GradesByPeriod groups grades by year and semester, ignoring grades whose year is unknown
+//
+// It also returns the periods in chronological order
+func GradesByPeriod(grades []Grade) (map[Period][]Grade, []Period) {
+	groups := make(map[Period][]Grade)
+	for _, g := range grades {
+		if g.Year != 0 {
+			p := Period{Year: g.Year, Semester: g.Semester}
+			groups[p] = append(groups[p], g)
+		}
+	}
+
+	periods := make([]Period, 0, len(groups))
+	for p := range groups {
+		periods = append(periods, p)
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Before(periods[j]) })
+	return groups, periods
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGradeDistribution(t *testing.T) {
+	dist := NewGradeDistribution([]Grade{{Value: 4.0}, {Value: 9.0}, {Value: 9.04}})
+
+	assert.Equal(t, 3, dist.Count)
+	assert.Equal(t, map[string]int{"4.0": 1, "9.0": 2}, dist.Buckets)
+	assert.InDelta(t, 22.04/3, dist.Average, 1e-9)
+	assert.InDelta(t, 2.0/3, dist.Approval, 1e-9)
+
+	empty := NewGradeDistribution(nil)
+	assert.Equal(t, 0, empty.Count)
+	assert.Equal(t, 0.0, empty.Average)
+	assert.Empty(t, empty.Buckets)
+}
+
+func TestOfferingInYear(t *testing.T) {
+	offerings := map[string]*Offering{
+		"a": {Professor: "A", Years: []string{"2019", "2020"}},
+		"b": {Professor: "B", Years: []string{"2020", "2021"}},
+	}
+
+	assert.Equal(t, "a", OfferingInYear(offerings, 2019))
+	assert.Equal(t, "", OfferingInYear(offerings, 2020), "two professors taught in 2020")
+	assert.Equal(t, "b", OfferingInYear(offerings, 2021))
+	assert.Equal(t, "", OfferingInYear(offerings, 2022))
+	assert.Equal(t, "", OfferingInYear(offerings, 0))
+}
+
+func TestGradesByPeriod(t *testing.T) {
+	grades := []Grade{
+		{Value: 5, Year: 2021, Semester: 2},
+		{Value: 6, Year: 2020, Semester: 1},
+		{Value: 7, Year: 2021, Semester: 1},
+		{Value: 8, Year: 2021, Semester: 1},
+		{Value: 9}, // legacy grade without year
+	}
+
+	groups, periods := GradesByPeriod(grades)
+	assert.Equal(t, []Period{{2020, 1}, {2021, 1}, {2021, 2}}, periods)
+	assert.Len(t, groups[Period{2021, 1}], 2)
+
+	years := GradesByYear(grades)
+	assert.Len(t, years, 2)
+	assert.Len(t, years[2021], 3)
+
+	offerings := GradesByOffering(grades)
+	assert.Len(t, offerings[""], 5)
+}
@@ -2,6 +2,7 @@
 package models
 
 import (
+	"cloud.google.com/go/firestore"
 	"github.com/Projeto-USPY/uspy-backend/db"
 	"github.com/Projeto-USPY/uspy-backend/utils"
 )
@@ -14,6 +15,8 @@ type Offering struct {
 
 	Professor string   `firestore:"professor"`
 	Years     []string `firestore:"years"`
+
+	Stats OfferingStats `firestore:"stats"`
 }
 
 // sha256(CodPes)
@@ -21,12 +24,15 @@ func (off Offering) Hash() string {
 	return utils.SHA256(off.CodPes)
 }
 
+// offeringFields are the fields written by Insert and Update, stats are only changed by comments
+var offeringFields = firestore.Merge([]string{"professor"}, []string{"years"})
+
 func (off Offering) Insert(DB db.Env, collection string) error {
-	_, err := DB.Client.Collection(collection).Doc(off.Hash()).Set(DB.Ctx, off)
+	_, err := DB.Client.Collection(collection).Doc(off.Hash()).Set(DB.Ctx, off, offeringFields)
 	return err
 }
 
 func (off Offering) Update(DB db.Env, collection string) error {
-	_, err := DB.Client.Collection(collection).Doc(off.Hash()).Set(DB.Ctx, off)
+	_, err := DB.Client.Collection(collection).Doc(off.Hash()).Set(DB.Ctx, off, offeringFields)
 	return err
 }
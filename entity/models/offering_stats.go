@@ -1,7 +1,74 @@
 package models
 
+import "cloud.google.com/go/firestore"
+
+// OfferingStats counts the ratings of every comment made about an offering
+//
+// It is stored in the offering document and kept up to date whenever a comment is published, edited or deleted
 type OfferingStats struct {
-	Approval    int
-	Disapproval int
-	Neutral     int
+	Approval    int `firestore:"approval"`
+	Disapproval int `firestore:"disapproval"`
+	Neutral     int `firestore:"neutral"`
+}
+
+// ratingBucket returns the field a comment rating is counted in:
+// ratings below 3 are negative, above 3 are positive and 3 is neutral
+func ratingBucket(rating int) string {
+	if rating < 3 {
+		return "disapproval"
+	} else if rating > 3 {
+		return "approval"
+	}
+
+	return "neutral"
+}
+
+// Add counts a comment rating
+func (s *OfferingStats) Add(rating int) {
+	switch ratingBucket(rating) {
+	case "disapproval":
+		s.Disapproval++
+	case "approval":
+		s.Approval++
+	default:
+		s.Neutral++
+	}
+}
+
+// Merge adds the counts of other to s
+func (s *OfferingStats) Merge(other OfferingStats) {
+	s.Approval += other.Approval
+	s.Disapproval += other.Disapproval
+	s.Neutral += other.Neutral
+}
+
+// Total returns the number of ratings
+func (s OfferingStats) Total() int {
+	return s.Approval + s.Disapproval + s.Neutral
+}
+
+// CommentStatsUpdates returns the updates that replace the removed comment by the added one in their offering's stats
+//
+// Either comment may be nil. If both ratings fall in the same bucket, there is nothing to update.
+func CommentStatsUpdates(removed, added *Comment) []firestore.Update {
+	deltas := make(map[string]int)
+	if removed != nil {
+		deltas[ratingBucket(removed.Rating)]--
+	}
+
+	if added != nil {
+		deltas[ratingBucket(added.Rating)]++
+	}
+
+	updates := make([]firestore.Update, 0, 2)
+	for _, bucket := range []string{"approval", "disapproval", "neutral"} {
+		if delta := deltas[bucket]; delta != 0 {
+			updates = append(updates, firestore.Update{
+				FieldPath: firestore.FieldPath{"stats", bucket},
+				Value:     firestore.Increment(delta),
+			})
+		}
+	}
+
+	return updates
 }
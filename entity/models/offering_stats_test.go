@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentStatsUpdates(t *testing.T) {
+	good, neutral, bad := &Comment{Rating: 5}, &Comment{Rating: 3}, &Comment{Rating: 1}
+
+	assert.Equal(t, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"stats", "approval"}, Value: firestore.Increment(1)},
+	}, CommentStatsUpdates(nil, good), "new comment")
+
+	assert.Equal(t, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"stats", "disapproval"}, Value: firestore.Increment(-1)},
+		{FieldPath: firestore.FieldPath{"stats", "neutral"}, Value: firestore.Increment(1)},
+	}, CommentStatsUpdates(bad, neutral), "edited rating")
+
+	assert.Empty(t, CommentStatsUpdates(good, &Comment{Rating: 4}), "same bucket")
+
+	assert.Equal(t, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"stats", "neutral"}, Value: firestore.Increment(-1)},
+	}, CommentStatsUpdates(neutral, nil), "deleted comment")
+}
+
+func TestOfferingStatsAdd(t *testing.T) {
+	var stats OfferingStats
+	for _, r := range []int{1, 2, 3, 4, 5, 5} {
+		stats.Add(r)
+	}
+
+	assert.Equal(t, OfferingStats{Approval: 3, Disapproval: 2, Neutral: 1}, stats)
+	assert.Equal(t, 6, stats.Total())
+}
@@ -0,0 +1,286 @@
+package models
+
+import (
+	"sort"
+)
+
+// PrerequisiteEdge connects a requirement to the subject that requires it
+type PrerequisiteEdge struct {
+	From   string
+	To     string
+	Strong bool
+}
+
+// PrerequisiteNode is a subject in the prerequisite graph
+//
+// External nodes are requirements that do not belong to the graph's course, so only their code and name are known
+type PrerequisiteNode struct {
+	Subject  Subject
+	External bool
+}
+
+// PrerequisiteGraph is the directed graph of requirements between the subjects of a course
+//
+// Nodes are keyed by subject code and edges go from the requirement to the subject that requires it.
+// The graph is expected to be acyclic, but since it comes from scraped data, cycles are tolerated and can be reported by Cycles.
+type PrerequisiteGraph struct {
+	Nodes map[string]*PrerequisiteNode
+	Edges []PrerequisiteEdge
+
+	successors   map[string][]int // edge indexes, keyed by edge origin
+	predecessors map[string][]int // edge indexes, keyed by edge destination
+}
+
+// NewPrerequisiteGraph builds the prerequisite graph for the given subjects
+//
+// A requirement that appears in more than one requirement group is considered strong if any of its occurrences is strong
+func NewPrerequisiteGraph(subjects []Subject) *PrerequisiteGraph {
+	g := newEmptyPrerequisiteGraph()
+
+	for _, s := range subjects {
+		g.Nodes[s.Code] = &PrerequisiteNode{Subject: s}
+	}
+
+	for _, s := range subjects {
+		strength := make(map[string]bool)
+		names := make(map[string]string)
+		for _, group := range s.Requirements {
+			for _, r := range group {
+				strength[r.Subject] = strength[r.Subject] || r.Strong
+				names[r.Subject] = r.Name
+			}
+		}
+
+		for _, code := range sortedKeys(strength) {
+			if _, ok := g.Nodes[code]; !ok {
+				g.Nodes[code] = &PrerequisiteNode{
+					Subject:  Subject{Code: code, Name: names[code]},
+					External: true,
+				}
+			}
+
+			g.addEdge(PrerequisiteEdge{From: code, To: s.Code, Strong: strength[code]})
+		}
+	}
+
+	return g
+}
+
+func newEmptyPrerequisiteGraph() *PrerequisiteGraph {
+	return &PrerequisiteGraph{
+		Nodes:        make(map[string]*PrerequisiteNode),
+		Edges:        make([]PrerequisiteEdge, 0),
+		successors:   make(map[string][]int),
+		predecessors: make(map[string][]int),
+	}
+}
+
+func (g *PrerequisiteGraph) addEdge(e PrerequisiteEdge) {
+	g.Edges = append(g.Edges, e)
+	g.successors[e.From] = append(g.successors[e.From], len(g.Edges)-1)
+	g.predecessors[e.To] = append(g.predecessors[e.To], len(g.Edges)-1)
+}
+
+// Codes returns the codes of all subjects in the graph, sorted
+func (g *PrerequisiteGraph) Codes() []string {
+	codes := make([]string, 0, len(g.Nodes))
+	for c := range g.Nodes {
+		codes = append(codes, c)
+	}
+
+	sort.Strings(codes)
+	return codes
+}
+
+// Predecessors returns the edges that arrive at the given subject, that is, its direct requirements
+func (g *PrerequisiteGraph) Predecessors(code string) []PrerequisiteEdge {
+	edges := make([]PrerequisiteEdge, 0, len(g.predecessors[code]))
+	for _, i := range g.predecessors[code] {
+		edges = append(edges, g.Edges[i])
+	}
+
+	return edges
+}
+
+// Successors returns the edges that leave the given subject, that is, the subjects that directly require it
+func (g *PrerequisiteGraph) Successors(code string) []PrerequisiteEdge {
+	edges := make([]PrerequisiteEdge, 0, len(g.successors[code]))
+	for _, i := range g.successors[code] {
+		edges = append(edges, g.Edges[i])
+	}
+
+	return edges
+}
+
+// Closure returns the subgraph with the given subject and all its transitive predecessors and/or successors
+func (g *PrerequisiteGraph) Closure(code string, predecessors, successors bool) *PrerequisiteGraph {
+	visited := map[string]bool{code: true}
+
+	walk := func(next func(string) []string) {
+		queue := []string{code}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+
+			for _, n := range next(cur) {
+				if !visited[n] {
+					visited[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+
+	if predecessors {
+		walk(func(c string) []string {
+			result := make([]string, 0)
+			for _, e := range g.Predecessors(c) {
+				result = append(result, e.From)
+			}
+			return result
+		})
+	}
+
+	if successors {
+		walk(func(c string) []string {
+			result := make([]string, 0)
+			for _, e := range g.Successors(c) {
+				result = append(result, e.To)
+			}
+			return result
+		})
+	}
+
+	sub := newEmptyPrerequisiteGraph()
+	for c := range visited {
+		if node, ok := g.Nodes[c]; ok {
+			sub.Nodes[c] = node
+		}
+	}
+
+	for _, e := range g.Edges {
+		if visited[e.From] && visited[e.To] {
+			sub.addEdge(e)
+		}
+	}
+
+	return sub
+}
+
+// components returns the strongly connected components of the graph, in reverse topological order (Tarjan's algorithm)
+func (g *PrerequisiteGraph) components() [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := make([]string, 0)
+	result := make([][]string, 0)
+	counter := 0
+
+	var connect func(v string)
+	connect = func(v string) {
+		index[v], low[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, e := range g.Successors(v) {
+			w := e.To
+			if _, seen := index[w]; !seen {
+				connect(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] && index[w] < low[v] {
+				low[v] = index[w]
+			}
+		}
+
+		if low[v] == index[v] {
+			component := make([]string, 0, 1)
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+
+			sort.Strings(component)
+			result = append(result, component)
+		}
+	}
+
+	for _, c := range g.Codes() {
+		if _, seen := index[c]; !seen {
+			connect(c)
+		}
+	}
+
+	return result
+}
+
+// Cycles returns every group of subjects that require each other, directly or transitively
+func (g *PrerequisiteGraph) Cycles() [][]string {
+	cycles := make([][]string, 0)
+	for _, c := range g.components() {
+		if len(c) > 1 {
+			cycles = append(cycles, c)
+			continue
+		}
+
+		for _, e := range g.Successors(c[0]) {
+			if e.To == c[0] { // subject requires itself
+				cycles = append(cycles, c)
+				break
+			}
+		}
+	}
+
+	return cycles
+}
+
+// Levels returns the depth of each subject: the length of the longest chain of requirements that leads to it
+//
+// Subjects without requirements have depth 0. Subjects in a cycle are collapsed and share the same depth.
+func (g *PrerequisiteGraph) Levels() map[string]int {
+	components := g.components()
+
+	componentOf := make(map[string]int)
+	for i, c := range components {
+		for _, code := range c {
+			componentOf[code] = i
+		}
+	}
+
+	// Tarjan returns components in reverse topological order, so iterate from the end
+	depth := make([]int, len(components))
+	for i := len(components) - 1; i >= 0; i-- {
+		for _, code := range components[i] {
+			for _, e := range g.Successors(code) {
+				j := componentOf[e.To]
+				if j != i && depth[i]+1 > depth[j] {
+					depth[j] = depth[i] + 1
+				}
+			}
+		}
+	}
+
+	levels := make(map[string]int, len(g.Nodes))
+	for code, i := range componentOf {
+		levels[code] = depth[i]
+	}
+
+	return levels
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func requires(code string, reqs ...Requirement) Subject {
+	return Subject{Code: code, Requirements: map[string][]Requirement{"0": reqs}}
+}
+
+func TestPrerequisiteGraphLevels(t *testing.T) {
+	g := NewPrerequisiteGraph([]Subject{
+		requires("A"),
+		requires("B", Requirement{Subject: "A", Strong: true}),
+		requires("C", Requirement{Subject: "A", Strong: true}, Requirement{Subject: "B", Strong: false}),
+		requires("D", Requirement{Subject: "X", Name: "External", Strong: true}),
+	})
+
+	expected := map[string]int{"A": 0, "B": 1, "C": 2, "D": 1, "X": 0}
+	if levels := g.Levels(); !reflect.DeepEqual(levels, expected) {
+		t.Fatalf("unexpected levels: %v", levels)
+	}
+
+	if !g.Nodes["X"].External {
+		t.Fatal("X should be an external subject")
+	}
+
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Fatalf("expected no cycles, got %v", cycles)
+	}
+}
+
+func TestPrerequisiteGraphCycles(t *testing.T) {
+	g := NewPrerequisiteGraph([]Subject{
+		requires("A", Requirement{Subject: "C", Strong: true}),
+		requires("B", Requirement{Subject: "A", Strong: true}),
+		requires("C", Requirement{Subject: "B", Strong: true}),
+		requires("D", Requirement{Subject: "C", Strong: true}),
+	})
+
+	if cycles := g.Cycles(); !reflect.DeepEqual(cycles, [][]string{{"A", "B", "C"}}) {
+		t.Fatalf("unexpected cycles: %v", cycles)
+	}
+
+	if levels := g.Levels(); levels["D"] != 1 || levels["A"] != 0 {
+		t.Fatalf("unexpected levels: %v", levels)
+	}
+}
+
+func TestPrerequisiteGraphClosure(t *testing.T) {
+	g := NewPrerequisiteGraph([]Subject{
+		requires("A"),
+		requires("B", Requirement{Subject: "A", Strong: true}),
+		requires("C", Requirement{Subject: "B", Strong: true}),
+		requires("D"),
+	})
+
+	if codes := g.Closure("B", true, false).Codes(); !reflect.DeepEqual(codes, []string{"A", "B"}) {
+		t.Fatalf("unexpected predecessors closure: %v", codes)
+	}
+
+	if codes := g.Closure("B", false, true).Codes(); !reflect.DeepEqual(codes, []string{"B", "C"}) {
+		t.Fatalf("unexpected successors closure: %v", codes)
+	}
+
+	if edges := g.Closure("C", true, true).Edges; len(edges) != 2 {
+		t.Fatalf("expected 2 edges in closure, got %v", edges)
+	}
+}
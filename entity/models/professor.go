@@ -1,18 +1,67 @@
 package models
 
-import "github.com/Projeto-USPY/uspy-backend/db"
+import (
+	"sort"
 
+	"github.com/Projeto-USPY/uspy-backend/db"
+)
+
+// ProfessorOffering is an offering of a subject, as stored in the professor document
+type ProfessorOffering struct {
+	Subject        string   `firestore:"subject"`
+	CourseCode     string   `firestore:"course"`
+	Specialization string   `firestore:"specialization"`
+	SubjectName    string   `firestore:"name"`
+	Years          []string `firestore:"years"`
+}
+
+// SubjectHash returns the hash of the offered subject
+func (po ProfessorOffering) SubjectHash() string {
+	return Subject{Code: po.Subject, CourseCode: po.CourseCode, Specialization: po.Specialization}.Hash()
+}
+
+// ProfessorComment is a comment made on one of the professor's offerings
+type ProfessorComment struct {
+	Offering ProfessorOffering
+	Author   string // comment document ID, the hash of its author
+	Comment  *Comment
+}
+
+// Professor aggregates every offering a professor taught, across all subjects
+//
+// It is stored in professors/{hash}, where hash is the same as the offerings hash: sha256(CodPes).
+// The collection is built from the offerings of every subject, see admin.BuildProfessors. Offerings are written by
+// uspy-scraper and the server never updates them, so build-professors must run after every scraper run
 type Professor struct {
-	CodPes string `firestore:"-"`
-	Name   string `firestore:"-"`
+	Hash string `firestore:"-"`
+
+	Name      string              `firestore:"name"`
+	Tokens    []string            `firestore:"tokens"` // prefixes of the normalized name words, used for searching
+	Offerings []ProfessorOffering `firestore:"offerings"`
+}
 
-	Offerings []Offering `firestore:"-"`
+func (p Professor) Insert(DB db.Env, collection string) error {
+	_, err := DB.Client.Collection(collection).Doc(p.Hash).Set(DB.Ctx, p)
+	return err
 }
 
-func (prof Professor) Insert(DB db.Env, collection string) error {
-	return nil
+func (p Professor) Update(DB db.Env, collection string) error {
+	_, err := DB.Client.Collection(collection).Doc(p.Hash).Set(DB.Ctx, p)
+	return err
 }
 
-func (prof Professor) Update(DB db.Env, collection string) error {
-	return nil
+// SortOfferings orders the professor's offerings by subject code and course
+func (p *Professor) SortOfferings() {
+	sort.Slice(p.Offerings, func(i, j int) bool {
+		a, b := p.Offerings[i], p.Offerings[j]
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+
+		if a.CourseCode != b.CourseCode {
+			return a.CourseCode < b.CourseCode
+		}
+
+		return a.Specialization < b.Specialization
+	})
 }
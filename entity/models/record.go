@@ -31,3 +31,28 @@ func (mf Record) Insert(DB db.Env, collection string) error {
 }
 
 func (mf Record) Update(DB db.Env, collection string) error { return nil }
+
+// Record status values, as they appear in the uspdigital transcript
+const (
+	StatusApproved       = "A"  // aprovado
+	StatusFailedGrade    = "RN" // reprovado por nota
+	StatusFailedAbsence  = "RF" // reprovado por frequência
+	StatusFailedGradeAbs = "RA" // reprovado por nota e frequência
+)
+
+// Approved reports whether the student passed the subject in this record
+func (mf Record) Approved() bool {
+	return mf.Status == StatusApproved
+}
+
+// Attended reports whether the student attended the subject with enough frequency, even if they failed it
+//
+// This is what weak requirements ask for
+func (mf Record) Attended() bool {
+	return mf.Approved() || mf.Status == StatusFailedGrade
+}
+
+// Failed reports whether the student failed the subject in this record
+func (mf Record) Failed() bool {
+	return mf.Status == StatusFailedGrade || mf.Status == StatusFailedAbsence || mf.Status == StatusFailedGradeAbs
+}
@@ -22,7 +22,7 @@ type Subject struct {
 	Requirements     map[string][]Requirement `firestore:"requirements"`
 	TrueRequirements []Requirement            `firestore:"true_requirements"`
 	Optional         bool                     `firestore:"optional"`
-	Stats            map[string]int           `firestore:"stats"`
+	Stats            SubjectStats             `firestore:"stats"`
 }
 
 func (s Subject) Hash() string {
@@ -30,6 +30,11 @@ func (s Subject) Hash() string {
 	return utils.SHA256(str)
 }
 
+// Credits returns the total number of credits of the subject (class and assignment credits)
+func (s Subject) Credits() int {
+	return s.ClassCredits + s.AssignCredits
+}
+
 func NewSubjectFromController(sub *controllers.Subject) *Subject {
 	return &Subject{Code: sub.Code, CourseCode: sub.CourseCode, Specialization: sub.Specialization}
 }
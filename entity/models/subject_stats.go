@@ -0,0 +1,314 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/db"
+)
+
+// CategoryStats aggregates every answer given to a review category
+//
+// Bool answers count as 1 (true) or 0 (false) in the sum, so Sum / Count is the approval rate
+type CategoryStats struct {
+	Count     int            `firestore:"count"`
+	Sum       float64        `firestore:"sum"`
+	Histogram map[string]int `firestore:"histogram"`
+}
+
+// LegacyCategory is the only review category of the legacy stats format
+const LegacyCategory = "worth_it"
+
+// SubjectStats is stored in each subject document and aggregates all of its reviews
+type SubjectStats struct {
+	Total      int                      `firestore:"total"`
+	Categories map[string]CategoryStats `firestore:"categories"`
+
+	// LegacyWorthIt is the number of positive worth_it answers of subjects that were not migrated yet, see Normalize
+	LegacyWorthIt *int `firestore:"worth_it,omitempty"`
+}
+
+// NewSubjectStats returns empty stats for the configured review categories
+func NewSubjectStats() SubjectStats {
+	stats := SubjectStats{Categories: make(map[string]CategoryStats)}
+	for _, c := range config.Env.Reviews.Categories() {
+		stats.Categories[c.Name] = CategoryStats{Histogram: make(map[string]int)}
+	}
+
+	return stats
+}
+
+// StatsCounter returns the sharded counter that holds the review stats of the subject with the given hash
+//
+// The stats stored in the subject document are the base value, to which the counter is added when the subject is read
+func StatsCounter(DB db.Env, subHash string) db.ShardedCounter {
+	return db.NewShardedCounter(DB.Client.Collection("subjects/"+subHash+"/stats_shards"), "stats")
+}
+
+// AddCounter adds the values read from the stats counter to the stats
+func (s *SubjectStats) AddCounter(values map[string]interface{}) {
+	s.Total += db.CounterInt(values, "total")
+
+	if s.Categories == nil {
+		s.Categories = make(map[string]CategoryStats)
+	}
+
+	for name := range db.CounterMap(values, "categories") {
+		stats := s.Categories[name]
+		stats.Count += db.CounterInt(values, "categories", name, "count")
+		stats.Sum += db.CounterFloat(values, "categories", name, "sum")
+
+		if stats.Histogram == nil {
+			stats.Histogram = make(map[string]int)
+		}
+
+		for bucket := range db.CounterMap(values, "categories", name, "histogram") {
+			stats.Histogram[bucket] += db.CounterInt(values, "categories", name, "histogram", bucket)
+		}
+
+		s.Categories[name] = stats
+	}
+}
+
+// AddReview counts a review in the stats, ignoring answers to categories that are no longer configured
+func (s *SubjectStats) AddReview(review *SubjectReview) {
+	s.Total++
+
+	if s.Categories == nil {
+		s.Categories = make(map[string]CategoryStats)
+	}
+
+	for name, answer := range review.Review {
+		category, ok := config.Env.Reviews.Category(name)
+		if !ok {
+			continue
+		}
+
+		value, bucket, err := ReviewValue(category, answer)
+		if err != nil {
+			continue
+		}
+
+		stats := s.Categories[name]
+		if stats.Histogram == nil {
+			stats.Histogram = make(map[string]int)
+		}
+
+		stats.Count++
+		stats.Sum += value
+		stats.Histogram[bucket]++
+		s.Categories[name] = stats
+	}
+}
+
+// Normalize folds the legacy worth_it count into the categories, so stats that were not migrated are not lost
+//
+// Legacy stats count reviews in Total and their positive worth_it answers in LegacyWorthIt. Reviews counted in the
+// document after categories were introduced are in both Total and the categories, and all of them answered worth_it,
+// so the legacy reviews are the ones missing from its count
+func (s *SubjectStats) Normalize() {
+	if s.LegacyWorthIt == nil {
+		return
+	}
+
+	positive := *s.LegacyWorthIt
+	s.LegacyWorthIt = nil
+
+	if s.Categories == nil {
+		s.Categories = make(map[string]CategoryStats)
+	}
+
+	stats := s.Categories[LegacyCategory]
+	if stats.Histogram == nil {
+		stats.Histogram = make(map[string]int)
+	}
+
+	legacyReviews := s.Total - stats.Count
+	stats.Count += legacyReviews
+	stats.Sum += float64(positive)
+	stats.Histogram["true"] += positive
+	stats.Histogram["false"] += legacyReviews - positive
+	s.Categories[LegacyCategory] = stats
+}
+
+// Clone returns a copy of the stats that shares no maps with the original
+func (s SubjectStats) Clone() SubjectStats {
+	clone := SubjectStats{Total: s.Total}
+	if s.LegacyWorthIt != nil {
+		positive := *s.LegacyWorthIt
+		clone.LegacyWorthIt = &positive
+	}
+
+	if s.Categories == nil {
+		return clone
+	}
+
+	clone.Categories = make(map[string]CategoryStats, len(s.Categories))
+	for name, stats := range s.Categories {
+		histogram := make(map[string]int, len(stats.Histogram))
+		for k, v := range stats.Histogram {
+			histogram[k] = v
+		}
+
+		stats.Histogram = histogram
+		clone.Categories[name] = stats
+	}
+
+	return clone
+}
+
+// Equal reports whether both stats count the same reviews
+//
+// Empty categories and histogram buckets are ignored and sums are compared with a small tolerance
+func (s SubjectStats) Equal(other SubjectStats) bool {
+	if s.Total != other.Total {
+		return false
+	}
+
+	names := make(map[string]bool)
+	for name := range s.Categories {
+		names[name] = true
+	}
+	for name := range other.Categories {
+		names[name] = true
+	}
+
+	for name := range names {
+		a, b := s.Categories[name], other.Categories[name]
+		if a.Count != b.Count || math.Abs(a.Sum-b.Sum) > 1e-6 {
+			return false
+		}
+
+		buckets := make(map[string]bool)
+		for k := range a.Histogram {
+			buckets[k] = true
+		}
+		for k := range b.Histogram {
+			buckets[k] = true
+		}
+
+		for k := range buckets {
+			if a.Histogram[k] != b.Histogram[k] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ReviewValue converts an answer to a review category into its numeric value and histogram bucket
+//
+// It returns an error if the answer is not valid for the category
+func ReviewValue(category config.ReviewCategory, answer interface{}) (float64, string, error) {
+	switch category.Type {
+	case config.CategoryBool:
+		b, ok := answer.(bool)
+		if !ok {
+			return 0, "", fmt.Errorf("category %s expects a boolean", category.Name)
+		}
+
+		if b {
+			return 1, "true", nil
+		}
+		return 0, "false", nil
+
+	case config.CategoryOrdinal, config.CategoryRange:
+		var value float64
+		switch v := answer.(type) {
+		case float64:
+			value = v
+		case int64: // values read from firestore
+			value = float64(v)
+		case int:
+			value = float64(v)
+		default:
+			return 0, "", fmt.Errorf("category %s expects a number", category.Name)
+		}
+
+		if math.IsNaN(value) || value < category.Min || value > category.Max {
+			return 0, "", fmt.Errorf("category %s expects a number between %v and %v", category.Name, category.Min, category.Max)
+		}
+
+		if category.Type == config.CategoryOrdinal {
+			if value != math.Trunc(value) {
+				return 0, "", fmt.Errorf("category %s expects an integer", category.Name)
+			}
+
+			return value, strconv.FormatFloat(value, 'f', -1, 64), nil
+		}
+
+		bucket := category.Min + math.Floor((value-category.Min)/category.Step)*category.Step
+		return value, strconv.FormatFloat(bucket, 'f', -1, 64), nil
+	}
+
+	return 0, "", errors.New("unknown category type: " + category.Type)
+}
+
+// ReviewStatsUpdates returns the increments that replace the removed review by the added one in their subject's stats
+//
+// Paths are relative to the stats, which are kept in a sharded counter, see StatsCounter.
+// Either review may be nil. Increments to the same field are merged, because firestore rejects updates with repeated fields.
+// Answers to categories that are no longer configured are ignored.
+func ReviewStatsUpdates(removed, added *SubjectReview) []firestore.Update {
+	counts := make(map[string]int)
+	sums := make(map[string]float64)
+	paths := make(map[string]firestore.FieldPath)
+
+	apply := func(review *SubjectReview, sign int) {
+		if review == nil {
+			return
+		}
+
+		counts["total"] += sign
+		paths["total"] = firestore.FieldPath{"total"}
+
+		for name, answer := range review.Review {
+			category, ok := config.Env.Reviews.Category(name)
+			if !ok {
+				continue
+			}
+
+			value, bucket, err := ReviewValue(category, answer)
+			if err != nil {
+				continue
+			}
+
+			count, sum, hist := name+".count", name+".sum", name+".histogram."+bucket
+			paths[count] = firestore.FieldPath{"categories", name, "count"}
+			paths[sum] = firestore.FieldPath{"categories", name, "sum"}
+			paths[hist] = firestore.FieldPath{"categories", name, "histogram", bucket}
+
+			counts[count] += sign
+			counts[hist] += sign
+			sums[sum] += float64(sign) * value
+		}
+	}
+
+	apply(removed, -1)
+	apply(added, 1)
+
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	updates := make([]firestore.Update, 0, len(keys))
+	for _, k := range keys {
+		if delta, ok := sums[k]; ok {
+			if delta != 0 {
+				updates = append(updates, firestore.Update{FieldPath: paths[k], Value: firestore.Increment(delta)})
+			}
+		} else if delta := counts[k]; delta != 0 {
+			updates = append(updates, firestore.Update{FieldPath: paths[k], Value: firestore.Increment(delta)})
+		}
+	}
+
+	return updates
+}
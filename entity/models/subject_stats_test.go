@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewValue(t *testing.T) {
+	workload, _ := config.Env.Reviews.Category("workload")
+	value, bucket, err := ReviewValue(workload, 12.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 12.5, value)
+	assert.Equal(t, "10", bucket)
+
+	difficulty, _ := config.Env.Reviews.Category("difficulty")
+	_, _, err = ReviewValue(difficulty, 2.5)
+	assert.Error(t, err, "ordinal answers must be integers")
+
+	_, _, err = ReviewValue(difficulty, int64(6))
+	assert.Error(t, err, "answers must be in range")
+
+	worthIt, _ := config.Env.Reviews.Category("worth_it")
+	_, _, err = ReviewValue(worthIt, 1.0)
+	assert.Error(t, err, "bool categories expect booleans")
+}
+
+func TestReviewStatsUpdates(t *testing.T) {
+	stored := &SubjectReview{Review: map[string]interface{}{"worth_it": true, "difficulty": int64(4)}}
+	updated := &SubjectReview{Review: map[string]interface{}{"worth_it": true, "difficulty": 2.0}}
+
+	// total and worth_it do not change, so only difficulty is updated
+	assert.Equal(t, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"categories", "difficulty", "histogram", "2"}, Value: firestore.Increment(1)},
+		{FieldPath: firestore.FieldPath{"categories", "difficulty", "histogram", "4"}, Value: firestore.Increment(-1)},
+		{FieldPath: firestore.FieldPath{"categories", "difficulty", "sum"}, Value: firestore.Increment(-2.0)},
+	}, ReviewStatsUpdates(stored, updated))
+
+	assert.Len(t, ReviewStatsUpdates(nil, updated), 7, "total and count, sum and histogram of each category")
+}
+
+func TestSubjectStatsAddCounter(t *testing.T) {
+	stats := NewSubjectStats()
+	stats.Total = 1
+
+	stats.AddCounter(map[string]interface{}{
+		"total": int64(2),
+		"categories": map[string]interface{}{
+			"worth_it": map[string]interface{}{
+				"count":     int64(2),
+				"sum":       int64(1),
+				"histogram": map[string]interface{}{"true": int64(1), "false": int64(1)},
+			},
+		},
+	})
+
+	assert.Equal(t, 3, stats.Total)
+	assert.Equal(t, CategoryStats{Count: 2, Sum: 1, Histogram: map[string]int{"true": 1, "false": 1}}, stats.Categories["worth_it"])
+}
+
+func TestSubjectStatsAddReview(t *testing.T) {
+	var stats SubjectStats
+	stats.AddReview(&SubjectReview{Review: map[string]interface{}{"worth_it": true, "difficulty": int64(4)}})
+	stats.AddReview(&SubjectReview{Review: map[string]interface{}{"worth_it": false}})
+
+	assert.Equal(t, 2, stats.Total)
+	assert.Equal(t, CategoryStats{Count: 2, Sum: 1, Histogram: map[string]int{"true": 1, "false": 1}}, stats.Categories["worth_it"])
+
+	// empty categories are the same as missing ones
+	expected := NewSubjectStats()
+	expected.Total = 2
+	expected.Categories["worth_it"] = stats.Categories["worth_it"]
+	expected.Categories["difficulty"] = stats.Categories["difficulty"]
+	assert.True(t, expected.Equal(stats))
+
+	expected.Total = 3
+	assert.False(t, expected.Equal(stats))
+}
+
+func TestNormalizeLegacyStats(t *testing.T) {
+	// 5 legacy reviews, 3 of them worth it. Since categories were introduced, 2 reviews were added (one worth it)
+	// and a legacy positive review was removed, which the legacy count does not know about
+	positive := 3
+	stats := SubjectStats{
+		Total:         6,
+		LegacyWorthIt: &positive,
+		Categories: map[string]CategoryStats{
+			"worth_it": {Count: 1, Sum: 0, Histogram: map[string]int{"true": 0, "false": 1}},
+		},
+	}
+
+	stats.Normalize()
+	assert.Nil(t, stats.LegacyWorthIt)
+	assert.Equal(t, CategoryStats{Count: 6, Sum: 3, Histogram: map[string]int{"true": 3, "false": 3}}, stats.Categories["worth_it"])
+
+	stats.Normalize()
+	assert.Equal(t, 6, stats.Categories["worth_it"].Count, "normalizing twice changes nothing")
+
+	// stats that were never migrated, nor reviewed since
+	positive = 1
+	legacy := SubjectStats{Total: 2, LegacyWorthIt: &positive}
+	legacy.Normalize()
+	assert.Equal(t, CategoryStats{Count: 2, Sum: 1, Histogram: map[string]int{"true": 1, "false": 1}}, legacy.Categories["worth_it"])
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/Projeto-USPY/uspy-backend/utils"
 )
 
+// UserComment is the author's copy of a comment, stored under the user
+//
+// Its votes are not updated when the comment is rated, they are reconciled with the sharded counter by the consistency job
 type UserComment struct {
 	Comment `firestore:"comment"`
 
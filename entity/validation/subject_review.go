@@ -1,35 +1,33 @@
 package validation
 
 import (
-	"reflect"
-	"sort"
-
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
 	"github.com/go-playground/validator/v10"
 )
 
-func getCategories() []string {
-	return []string{"worth_it"}
-}
-
+// validateSubjectReview checks that every answer belongs to a configured category and is valid for its type
+//
+// Required categories must always be answered, the others may be omitted
 func validateSubjectReview(f1 validator.FieldLevel) bool {
-	keys := f1.Field().MapKeys()
-	keysStr := make([]string, 0)
-
-	for _, k := range keys {
-		keysStr = append(keysStr, k.String())
+	review, ok := f1.Field().Interface().(map[string]interface{})
+	if !ok || len(review) == 0 {
+		return false
 	}
 
-	categories := getCategories()
+	for name, answer := range review {
+		category, ok := config.Env.Reviews.Category(name)
+		if !ok {
+			return false
+		}
 
-	if len(keys) != len(categories) {
-		return false
+		if _, _, err := models.ReviewValue(category, answer); err != nil {
+			return false
+		}
 	}
 
-	sort.Strings(keysStr)
-	sort.Strings(categories)
-
-	for i := 0; i < len(keys); i++ {
-		if !reflect.DeepEqual(keys[i].String(), categories[i]) {
+	for _, c := range config.Env.Reviews.Categories() {
+		if _, answered := review[c.Name]; c.Required && !answered {
 			return false
 		}
 	}
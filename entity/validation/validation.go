@@ -2,6 +2,8 @@ package validation
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -16,18 +18,30 @@ var validators = map[string]func(validator.FieldLevel) bool{
 	"validateRecoveryToken":     validateRecoveryToken,
 }
 
+// fieldName names fields as clients send them, by their json or form tag, so validation errors can be shown to them
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name := strings.Split(f.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
 // SetupValidators registers the default validation functions designed for each entity
 func SetupValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("failed to setup validators")
+	}
+
 	for key, value := range validators {
-		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-			err := v.RegisterValidation(key, value)
-			if err != nil {
-				return err
-			}
-		} else {
-			return errors.New("failed to setup validators")
+		if err := v.RegisterValidation(key, value); err != nil {
+			return err
 		}
 	}
 
+	v.RegisterTagNameFunc(fieldName)
 	return nil
 }
@@ -0,0 +1,101 @@
+package views
+
+import "github.com/Projeto-USPY/uspy-backend/entity/models"
+
+// planReasonMessages are short descriptions for each plan reason code
+var planReasonMessages = map[string]string{
+	models.ReasonRecommendedSemester: "Semestre recomendado pela grade do curso.",
+	models.ReasonRequirementApproved: "Requisito já aprovado.",
+	models.ReasonRequirementAttended: "Requisito fraco cumprido por uma tentativa anterior.",
+	models.ReasonRequirementPlanned:  "Requisito planejado para um semestre anterior.",
+	models.ReasonRequiredBy:          "Não é obrigatória, mas é requisito de outra disciplina do plano.",
+	models.ReasonCreditCap:           "Adiada porque o limite de créditos do semestre foi atingido.",
+	models.ReasonExceedsCreditCap:    "Possui mais créditos do que o limite por semestre.",
+	models.ReasonMissingRequirement:  "Possui um requisito que não faz parte do curso e ainda não foi cursado.",
+	models.ReasonRequirementCycle:    "Faz parte de um ciclo de requisitos.",
+	models.ReasonBlockedBy:           "Depende de uma disciplina que não pôde ser planejada.",
+	models.ReasonSemesterLimit:       "O número máximo de semestres do plano foi atingido.",
+}
+
+type PlanReason struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Subject  string `json:"subject,omitempty"`
+	Semester int    `json:"semester,omitempty"`
+	Strong   *bool  `json:"strong,omitempty"`
+}
+
+type PlannedSubject struct {
+	Code     string       `json:"code"`
+	Name     string       `json:"name"`
+	Credits  int          `json:"credits"`
+	Optional bool         `json:"optional"`
+	Reasons  []PlanReason `json:"reasons"`
+}
+
+type PlannedSemester struct {
+	Index    int              `json:"semester"`
+	Credits  int              `json:"credits"`
+	Subjects []PlannedSubject `json:"subjects"`
+}
+
+type CurriculumPlan struct {
+	Course         string            `json:"course"`
+	Specialization string            `json:"specialization"`
+	CreditCap      int               `json:"credit_cap"`
+	Semesters      []PlannedSemester `json:"semesters"`
+	Unscheduled    []PlannedSubject  `json:"unscheduled"`
+}
+
+func newPlannedSubject(model *models.PlannedSubject) PlannedSubject {
+	result := PlannedSubject{
+		Code:     model.Subject.Code,
+		Name:     model.Subject.Name,
+		Credits:  model.Credits,
+		Optional: model.Subject.Optional,
+		Reasons:  make([]PlanReason, 0, len(model.Reasons)),
+	}
+
+	for _, r := range model.Reasons {
+		reason := PlanReason{
+			Code:     r.Code,
+			Message:  planReasonMessages[r.Code],
+			Subject:  r.Subject,
+			Semester: r.Semester,
+		}
+
+		if r.Subject != "" { // strength only makes sense when the reason refers to a requirement
+			strong := r.Strong
+			reason.Strong = &strong
+		}
+
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	return result
+}
+
+func NewCurriculumPlanFromModel(course, specialization string, model *models.CurriculumPlan) *CurriculumPlan {
+	plan := CurriculumPlan{
+		Course:         course,
+		Specialization: specialization,
+		CreditCap:      model.CreditCap,
+		Semesters:      make([]PlannedSemester, 0, len(model.Semesters)),
+		Unscheduled:    make([]PlannedSubject, 0, len(model.Unscheduled)),
+	}
+
+	for _, s := range model.Semesters {
+		semester := PlannedSemester{Index: s.Index, Credits: s.Credits, Subjects: make([]PlannedSubject, 0, len(s.Subjects))}
+		for i := range s.Subjects {
+			semester.Subjects = append(semester.Subjects, newPlannedSubject(&s.Subjects[i]))
+		}
+
+		plan.Semesters = append(plan.Semesters, semester)
+	}
+
+	for i := range model.Unscheduled {
+		plan.Unscheduled = append(plan.Unscheduled, newPlannedSubject(&model.Unscheduled[i]))
+	}
+
+	return &plan
+}
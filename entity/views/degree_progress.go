@@ -0,0 +1,89 @@
+package views
+
+import "github.com/Projeto-USPY/uspy-backend/entity/models"
+
+type CreditProgress struct {
+	Done      int     `json:"done"`
+	Remaining int     `json:"remaining"`
+	Total     int     `json:"total"`
+	Ratio     float64 `json:"ratio"`
+}
+
+type OptionalProgress struct {
+	Done    int `json:"done"`
+	Offered int `json:"offered"`
+}
+
+type SemesterProgress struct {
+	Semester int            `json:"semester"`
+	Subjects int            `json:"subjects"`
+	Approved int            `json:"approved"`
+	Credits  CreditProgress `json:"credits"`
+}
+
+type FailedSubject struct {
+	Code     string   `json:"code"`
+	Name     string   `json:"name"`
+	Optional bool     `json:"optional"`
+	Attempts []Record `json:"attempts"`
+}
+
+type DegreeProgress struct {
+	Course         string             `json:"course"`
+	Specialization string             `json:"specialization"`
+	Mandatory      CreditProgress     `json:"mandatory"`
+	Optional       OptionalProgress   `json:"optional"`
+	Semesters      []SemesterProgress `json:"semesters"`
+	Failed         []FailedSubject    `json:"failed"`
+}
+
+func newCreditProgress(model models.CreditProgress) CreditProgress {
+	ratio := 0.0
+	if model.Total > 0 {
+		ratio = float64(model.Done) / float64(model.Total)
+	}
+
+	return CreditProgress{
+		Done:      model.Done,
+		Remaining: model.Remaining,
+		Total:     model.Total,
+		Ratio:     ratio,
+	}
+}
+
+func NewDegreeProgressFromModel(course, specialization string, model *models.DegreeProgress) *DegreeProgress {
+	progress := DegreeProgress{
+		Course:         course,
+		Specialization: specialization,
+		Mandatory:      newCreditProgress(model.Mandatory),
+		Optional:       OptionalProgress{Done: model.Optional.Done, Offered: model.Optional.Offered},
+		Semesters:      make([]SemesterProgress, 0, len(model.Semesters)),
+		Failed:         make([]FailedSubject, 0, len(model.Failed)),
+	}
+
+	for _, s := range model.Semesters {
+		progress.Semesters = append(progress.Semesters, SemesterProgress{
+			Semester: s.Semester,
+			Subjects: s.Subjects,
+			Approved: s.Approved,
+			Credits:  newCreditProgress(s.Credits),
+		})
+	}
+
+	for _, f := range model.Failed {
+		failed := FailedSubject{
+			Code:     f.Subject.Code,
+			Name:     f.Subject.Name,
+			Optional: f.Subject.Optional,
+			Attempts: make([]Record, 0, len(f.Attempts)),
+		}
+
+		for i := range f.Attempts {
+			failed.Attempts = append(failed.Attempts, *NewRecordFromModel(&f.Attempts[i]))
+		}
+
+		progress.Failed = append(progress.Failed, failed)
+	}
+
+	return &progress
+}
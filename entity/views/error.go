@@ -1,5 +1,7 @@
 package views
 
+import "github.com/Projeto-USPY/uspy-backend/i18n"
+
 var (
 	ErrInvalidCredentials = Error{Code: "invalid_credentials", Message: "Login ou senha incorretos."}
 	ErrInvalidEmail       = Error{Code: "invalid_email", Message: "Esse e-mail já está cadastrado."}
@@ -13,3 +15,28 @@ type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+// In translates the error's message by its code, the message is kept if there is no translation
+func (e Error) In(lang i18n.Language) Error {
+	e.Message = i18n.Message(lang, e.Code, e.Message)
+	return e
+}
+
+// ErrorEnvelope is the body of every error response of the /v2 API
+type ErrorEnvelope struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"` // why each invalid field of the request was rejected
+	RequestID string       `json:"request_id"`
+}
+
+// FieldError describes a request field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"` // validator tag, such as required or validatePassword
+	Message string `json:"message"`
+}
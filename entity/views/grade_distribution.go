@@ -0,0 +1,80 @@
+package views
+
+import (
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/privacy"
+)
+
+type GradeDistribution struct {
+	Grades   map[string]int `json:"grades"`
+	Average  float64        `json:"average"`
+	Approval float64        `json:"approval"`
+	Privacy
+}
+
+type ProfessorGrades struct {
+	ProfessorName string `json:"professor"`
+	ProfessorCode string `json:"code"`
+	GradeDistribution
+}
+
+type YearGrades struct {
+	Year int `json:"year"`
+	GradeDistribution
+}
+
+type PeriodGrades struct {
+	Year     int     `json:"year"`
+	Semester int     `json:"semester"`
+	Average  float64 `json:"average"`
+	Approval float64 `json:"approval"`
+	Privacy
+}
+
+func NewGradeDistributionFromModel(model models.GradeDistribution) GradeDistribution {
+	return GradeDistribution{
+		Grades:   model.Buckets,
+		Average:  model.Average,
+		Approval: model.Approval,
+	}
+}
+
+// NewProtectedGradeDistributionFromModel applies the grades privacy policy to a distribution
+func NewProtectedGradeDistributionFromModel(model models.GradeDistribution) GradeDistribution {
+	return protectGradeDistribution(model, privacy.Check(config.StatGrades, model.Count))
+}
+
+// NewProtectedGradeDistributionsFromModels applies the grades privacy policy to distributions that split the grades
+// of a subject, so that no hidden distribution can be recovered from the visible ones (see privacy.CheckSlices)
+func NewProtectedGradeDistributionsFromModels(dists []models.GradeDistribution) []GradeDistribution {
+	counts := make([]int, len(dists))
+	for i, d := range dists {
+		counts[i] = d.Count
+	}
+
+	results := make([]GradeDistribution, len(dists))
+	for i, outcome := range privacy.CheckSlices(config.StatGrades, counts) {
+		results[i] = protectGradeDistribution(dists[i], outcome)
+	}
+
+	return results
+}
+
+// protectGradeDistribution applies a privacy outcome to a distribution
+//
+// Suppressed distributions are empty, coarsened ones only tell which half of the grade scale the average is in
+func protectGradeDistribution(model models.GradeDistribution, outcome privacy.Outcome) GradeDistribution {
+	switch {
+	case outcome.Suppressed:
+		return GradeDistribution{Grades: map[string]int{}, Privacy: NewPrivacyFromOutcome(outcome)}
+	case outcome.Coarsened:
+		return GradeDistribution{
+			Grades:  map[string]int{},
+			Average: privacy.Coarsen(model.Average, 0, 10),
+			Privacy: NewPrivacyFromOutcome(outcome),
+		}
+	}
+
+	return NewGradeDistributionFromModel(model)
+}
@@ -0,0 +1,7 @@
+package views
+
+// Health is the body of the liveness and readiness probes
+type Health struct {
+	Status string            `json:"status"`           // ok, unavailable or draining
+	Checks map[string]string `json:"checks,omitempty"` // result of each readiness check, ok or failed
+}
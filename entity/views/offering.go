@@ -3,7 +3,9 @@ package views
 import (
 	"sort"
 
+	"github.com/Projeto-USPY/uspy-backend/config"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/privacy"
 )
 
 type Offering struct {
@@ -14,6 +16,7 @@ type Offering struct {
 	Approval    float64 `json:"approval"`
 	Neutral     float64 `json:"neutral"`
 	Disapproval float64 `json:"disapproval"`
+	Privacy
 }
 
 func SortOfferings(results []*Offering) {
@@ -41,7 +44,10 @@ func SortOfferings(results []*Offering) {
 	)
 }
 
-func NewOfferingFromModel(ID string, model *models.Offering, approval, disapproval, neutral int) *Offering {
+// ratingRates converts comment rating counts into rates, applying the given privacy outcome
+//
+// Rates are hidden when there are too few comments
+func ratingRates(approval, disapproval, neutral int, outcome privacy.Outcome) (float64, float64, float64, Privacy) {
 	total := (approval + disapproval + neutral)
 
 	approvalRate := 0.0
@@ -54,6 +60,18 @@ func NewOfferingFromModel(ID string, model *models.Offering, approval, disapprov
 		neutralRate = float64(neutral) / float64(total)
 	}
 
+	// rates of individual choices cannot be coarsened (with one comment they are 0 or 1), so both actions drop them
+	if !outcome.Visible() {
+		approvalRate, disapprovalRate, neutralRate = 0, 0, 0
+	}
+
+	return approvalRate, disapprovalRate, neutralRate, NewPrivacyFromOutcome(outcome)
+}
+
+func NewOfferingFromModel(ID string, model *models.Offering, approval, disapproval, neutral int) *Offering {
+	outcome := privacy.Check(config.StatOfferings, approval+disapproval+neutral)
+	approvalRate, disapprovalRate, neutralRate, rates := ratingRates(approval, disapproval, neutral, outcome)
+
 	return &Offering{
 		ProfessorName: model.Professor,
 		ProfessorCode: ID,
@@ -61,6 +79,7 @@ func NewOfferingFromModel(ID string, model *models.Offering, approval, disapprov
 		Approval:      approvalRate,
 		Disapproval:   disapprovalRate,
 		Neutral:       neutralRate,
+		Privacy:       rates,
 	}
 }
 
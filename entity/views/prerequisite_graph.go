@@ -0,0 +1,134 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+)
+
+type PrerequisiteNode struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Semester int    `json:"semester,omitempty"`
+	Optional bool   `json:"optional"`
+	External bool   `json:"external"`
+	Depth    int    `json:"depth"`
+}
+
+type PrerequisiteEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Strong bool   `json:"strong"`
+}
+
+type PrerequisiteGraph struct {
+	Course         string             `json:"course"`
+	Specialization string             `json:"specialization"`
+	Root           string             `json:"root,omitempty"`
+	Nodes          []PrerequisiteNode `json:"nodes"`
+	Edges          []PrerequisiteEdge `json:"edges"`
+	Cycles         [][]string         `json:"cycles"`
+}
+
+func NewPrerequisiteGraphFromModel(course, specialization, root string, model *models.PrerequisiteGraph) *PrerequisiteGraph {
+	levels := model.Levels()
+
+	graph := PrerequisiteGraph{
+		Course:         course,
+		Specialization: specialization,
+		Root:           root,
+		Nodes:          make([]PrerequisiteNode, 0, len(model.Nodes)),
+		Edges:          make([]PrerequisiteEdge, 0, len(model.Edges)),
+		Cycles:         model.Cycles(),
+	}
+
+	for _, code := range model.Codes() {
+		node := model.Nodes[code]
+		graph.Nodes = append(graph.Nodes, PrerequisiteNode{
+			Code:     code,
+			Name:     node.Subject.Name,
+			Semester: node.Subject.Semester,
+			Optional: node.Subject.Optional,
+			External: node.External,
+			Depth:    levels[code],
+		})
+	}
+
+	for _, e := range model.Edges {
+		graph.Edges = append(graph.Edges, PrerequisiteEdge{From: e.From, To: e.To, Strong: e.Strong})
+	}
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From == graph.Edges[j].From {
+			return graph.Edges[i].To < graph.Edges[j].To
+		}
+
+		return graph.Edges[i].From < graph.Edges[j].From
+	})
+
+	return &graph
+}
+
+// DOT renders the graph in the Graphviz DOT language
+//
+// Subjects with the same depth are placed in the same rank, weak requirements are dashed and external subjects are dotted
+func (g *PrerequisiteGraph) DOT() string {
+	var b strings.Builder
+
+	// backslashes are escaped first, so a trailing one cannot escape the closing quote
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+	quote := func(s string) string {
+		return `"` + escape(s) + `"`
+	}
+
+	name := g.Course + "-" + g.Specialization
+	if g.Root != "" {
+		name += "-" + g.Root
+	}
+
+	fmt.Fprintf(&b, "digraph %s {\n", quote(name))
+	b.WriteString("\trankdir=TB;\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	ranks := make(map[int][]string)
+	maxDepth := 0
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + `"` + escape(n.Code) + `\n` + escape(n.Name) + `"`}
+		if n.External {
+			attrs = append(attrs, "style=dotted")
+		} else if n.Optional {
+			attrs = append(attrs, "style=rounded")
+		}
+
+		if n.Code == g.Root {
+			attrs = append(attrs, "penwidth=2")
+		}
+
+		fmt.Fprintf(&b, "\t%s [%s];\n", quote(n.Code), strings.Join(attrs, ", "))
+
+		ranks[n.Depth] = append(ranks[n.Depth], quote(n.Code))
+		if n.Depth > maxDepth {
+			maxDepth = n.Depth
+		}
+	}
+
+	for d := 0; d <= maxDepth; d++ {
+		if len(ranks[d]) > 0 {
+			fmt.Fprintf(&b, "\t{ rank=same; %s; }\n", strings.Join(ranks[d], "; "))
+		}
+	}
+
+	for _, e := range g.Edges {
+		style := "solid"
+		if !e.Strong {
+			style = "dashed"
+		}
+
+		fmt.Fprintf(&b, "\t%s -> %s [style=%s];\n", quote(e.From), quote(e.To), style)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
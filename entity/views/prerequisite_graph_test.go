@@ -0,0 +1,20 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrerequisiteGraphDOTEscapesLabels(t *testing.T) {
+	graph := PrerequisiteGraph{
+		Course:         `a"b`,
+		Specialization: "0",
+		Nodes:          []PrerequisiteNode{{Code: "SCC0001", Name: `Tópicos \`}, {Code: `X"\`, Name: `"quoted"`}},
+	}
+
+	dot := graph.DOT()
+	assert.Contains(t, dot, `digraph "a\"b-0" {`)
+	assert.Contains(t, dot, `"SCC0001" [label="SCC0001\nTópicos \\"];`)
+	assert.Contains(t, dot, `"X\"\\" [label="X\"\\\n\"quoted\""];`)
+}
@@ -0,0 +1,13 @@
+package views
+
+import "github.com/Projeto-USPY/uspy-backend/privacy"
+
+// Privacy reports whether a statistic was changed because too few students contributed to it
+type Privacy struct {
+	Suppressed bool `json:"suppressed,omitempty"`
+	Coarsened  bool `json:"coarsened,omitempty"`
+}
+
+func NewPrivacyFromOutcome(outcome privacy.Outcome) Privacy {
+	return Privacy{Suppressed: outcome.Suppressed, Coarsened: outcome.Coarsened}
+}
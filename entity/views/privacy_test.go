@@ -0,0 +1,76 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func enforcePrivacy(t *testing.T) {
+	previous := config.Env
+	t.Cleanup(func() { config.Env = previous })
+
+	config.Env.Mode = config.ModeProd
+	config.Env.Privacy = config.Privacy{GradesPolicy: "11:suppress", OfferingsPolicy: "5:suppress", ReviewsPolicy: "5:suppress"}
+	require.NoError(t, config.Env.Privacy.Setup())
+}
+
+func TestProtectedGradeSlicesResidual(t *testing.T) {
+	enforcePrivacy(t)
+
+	grades := func(n int, value float64) []models.Grade {
+		result := make([]models.Grade, n)
+		for i := range result {
+			result[i] = models.Grade{Value: value}
+		}
+		return result
+	}
+
+	slices := [][]models.Grade{grades(3, 2.0), grades(20, 7.0), grades(30, 9.0)}
+
+	all := make([]models.Grade, 0)
+	dists := make([]models.GradeDistribution, 0, len(slices))
+	for _, s := range slices {
+		all = append(all, s...)
+		dists = append(dists, models.NewGradeDistribution(s))
+	}
+
+	// what a client sees: the subject's distribution and the distribution of each slice
+	total := NewProtectedGradeDistributionFromModel(models.NewGradeDistribution(all))
+	require.False(t, total.Suppressed)
+
+	residual := make(map[string]int)
+	for grade, count := range total.Grades {
+		residual[grade] = count
+	}
+
+	for _, dist := range NewProtectedGradeDistributionsFromModels(dists) {
+		for grade, count := range dist.Grades {
+			residual[grade] -= count
+		}
+	}
+
+	// the small slice is mixed with another one, so its grades cannot be told apart
+	assert.NotEqual(t, map[string]int{"2.0": 3}, residual)
+	assert.Equal(t, 3, residual["2.0"])
+	assert.Equal(t, 20, residual["7.0"])
+}
+
+func TestProfessorTotalHidesResidual(t *testing.T) {
+	enforcePrivacy(t)
+
+	prof := &models.Professor{Offerings: []models.ProfessorOffering{{}, {}}}
+
+	// the second subject has a single comment, which would be the difference between the total and the first subject
+	view := NewProfessorFromModel(prof, []models.OfferingStats{{Approval: 10}, {Disapproval: 1}})
+	assert.False(t, view.Subjects[0].Suppressed)
+	assert.True(t, view.Subjects[1].Suppressed)
+	assert.True(t, view.Suppressed)
+	assert.Zero(t, view.Disapproval)
+
+	view = NewProfessorFromModel(prof, []models.OfferingStats{{Approval: 10}, {Disapproval: 6}})
+	assert.False(t, view.Suppressed)
+}
@@ -0,0 +1,121 @@
+package views
+
+import (
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/privacy"
+)
+
+type ProfessorSubject struct {
+	Code           string   `json:"code"`
+	CourseCode     string   `json:"course"`
+	Specialization string   `json:"specialization"`
+	Name           string   `json:"name"`
+	Years          []string `json:"years"`
+
+	Approval    float64 `json:"approval"`
+	Neutral     float64 `json:"neutral"`
+	Disapproval float64 `json:"disapproval"`
+	Privacy
+}
+
+type Professor struct {
+	Code     string              `json:"code"`
+	Name     string              `json:"name"`
+	Subjects []*ProfessorSubject `json:"subjects"`
+
+	Approval    float64 `json:"approval"`
+	Neutral     float64 `json:"neutral"`
+	Disapproval float64 `json:"disapproval"`
+	Privacy
+}
+
+type ProfessorSearchResult struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Subjects int    `json:"subjects"`
+}
+
+type ProfessorComment struct {
+	*Comment
+	Subject        string `json:"subject"`
+	CourseCode     string `json:"course"`
+	Specialization string `json:"specialization"`
+	SubjectName    string `json:"name"`
+}
+
+type ProfessorComments struct {
+	Comments []*ProfessorComment `json:"comments"`
+	Next     string              `json:"next,omitempty"` // cursor of the next page, empty on the last one
+	Limit    int                 `json:"limit"`
+}
+
+func newProfessorSubject(off models.ProfessorOffering) *ProfessorSubject {
+	return &ProfessorSubject{
+		Code:           off.Subject,
+		CourseCode:     off.CourseCode,
+		Specialization: off.Specialization,
+		Name:           off.SubjectName,
+		Years:          off.Years,
+	}
+}
+
+// NewPartialProfessorFromModel returns the professor and their subjects without rating stats
+func NewPartialProfessorFromModel(model *models.Professor) *Professor {
+	prof := &Professor{
+		Code:     model.Hash,
+		Name:     model.Name,
+		Subjects: make([]*ProfessorSubject, 0, len(model.Offerings)),
+	}
+
+	for _, off := range model.Offerings {
+		prof.Subjects = append(prof.Subjects, newProfessorSubject(off))
+	}
+
+	return prof
+}
+
+// NewProfessorFromModel returns the professor with the rating stats of each subject and of all subjects together
+//
+// stats is indexed like model.Offerings. The rates of each subject are also shown in the subject's page, so if any of
+// them is hidden the total is hidden too, otherwise it could be recovered by subtracting the others from the total
+func NewProfessorFromModel(model *models.Professor, stats []models.OfferingStats) *Professor {
+	prof := NewPartialProfessorFromModel(model)
+
+	var total models.OfferingStats
+	hidden := false
+	for i, s := range stats {
+		outcome := privacy.Check(config.StatOfferings, s.Approval+s.Disapproval+s.Neutral)
+		hidden = hidden || !outcome.Visible()
+
+		sub := prof.Subjects[i]
+		sub.Approval, sub.Disapproval, sub.Neutral, sub.Privacy = ratingRates(s.Approval, s.Disapproval, s.Neutral, outcome)
+		total.Merge(s)
+	}
+
+	outcome := privacy.Check(config.StatOfferings, total.Approval+total.Disapproval+total.Neutral)
+	if hidden && outcome.Visible() {
+		outcome = privacy.Hidden(config.StatOfferings)
+	}
+
+	prof.Approval, prof.Disapproval, prof.Neutral, prof.Privacy = ratingRates(total.Approval, total.Disapproval, total.Neutral, outcome)
+	return prof
+}
+
+func NewProfessorSearchResultFromModel(model *models.Professor) *ProfessorSearchResult {
+	return &ProfessorSearchResult{
+		Code:     model.Hash,
+		Name:     model.Name,
+		Subjects: len(model.Offerings),
+	}
+}
+
+func NewProfessorCommentFromModel(off models.ProfessorOffering, model *models.Comment) *ProfessorComment {
+	return &ProfessorComment{
+		Comment:        NewCommentFromModel(model),
+		Subject:        off.Subject,
+		CourseCode:     off.CourseCode,
+		Specialization: off.Specialization,
+		SubjectName:    off.SubjectName,
+	}
+}
@@ -1,22 +1,84 @@
 package views
 
 import (
+	"github.com/Projeto-USPY/uspy-backend/config"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/privacy"
 )
 
 type Subject struct {
-	Code           string          `json:"code"`
-	CourseCode     string          `json:"course"`
-	Specialization string          `json:"specialization"`
-	Name           string          `json:"name"`
-	Description    string          `json:"description"`
-	Semester       int             `json:"semester"`
-	ClassCredits   int             `json:"class"`
-	AssignCredits  int             `json:"assign"`
-	TotalHours     string          `json:"hours"`
-	Optional       bool            `json:"optional"`
-	Stats          map[string]int  `json:"stats"`
-	Requirements   [][]Requirement `json:"requirements"`
+	Code           string                   `json:"code"`
+	CourseCode     string                   `json:"course"`
+	Specialization string                   `json:"specialization"`
+	Name           string                   `json:"name"`
+	Description    string                   `json:"description"`
+	Semester       int                      `json:"semester"`
+	ClassCredits   int                      `json:"class"`
+	AssignCredits  int                      `json:"assign"`
+	TotalHours     string                   `json:"hours"`
+	Optional       bool                     `json:"optional"`
+	Stats          map[string]int           `json:"stats"`
+	Reviews        map[string]CategoryStats `json:"reviews"`
+	Requirements   [][]Requirement          `json:"requirements"`
+}
+
+type CategoryStats struct {
+	Count     int            `json:"count"`
+	Average   float64        `json:"average"`
+	Histogram map[string]int `json:"histogram"`
+	Privacy
+}
+
+// transformStats returns the legacy stats map (total and number of positive answers to each boolean category)
+// along with the full stats of each review category
+//
+// Categories with too few answers are suppressed or coarsened according to the reviews privacy policy
+func transformStats(sub *models.Subject) (map[string]int, map[string]CategoryStats) {
+	legacy := map[string]int{"total": sub.Stats.Total}
+	reviews := make(map[string]CategoryStats)
+
+	for _, c := range config.Env.Reviews.Categories() {
+		stats := sub.Stats.Categories[c.Name]
+
+		average := 0.0
+		if stats.Count > 0 {
+			average = stats.Sum / float64(stats.Count)
+		}
+
+		histogram := make(map[string]int)
+		for k, v := range stats.Histogram {
+			if v > 0 {
+				histogram[k] = v
+			}
+		}
+
+		count, positive := stats.Count, stats.Histogram["true"]
+
+		outcome := privacy.Check(config.StatReviews, stats.Count)
+		if outcome.Suppressed {
+			average, positive, histogram = 0, 0, map[string]int{}
+		} else if outcome.Coarsened {
+			count, positive, histogram = 0, 0, map[string]int{}
+			if c.Type == config.CategoryBool {
+				average = 0 // the average of a yes/no category is a rate
+			} else {
+				average = privacy.Coarsen(average, c.Min, c.Max)
+			}
+		}
+
+		reviews[c.Name] = CategoryStats{
+			Count:     count,
+			Average:   average,
+			Histogram: histogram,
+			Privacy:   NewPrivacyFromOutcome(outcome),
+		}
+
+		if c.Type == config.CategoryBool {
+			legacy[c.Name] = positive
+		}
+	}
+
+	return legacy, reviews
 }
 
 // Transforms from map[string][]models.Requirement to [][]views.Requirement
@@ -37,6 +99,8 @@ func transformRequirements(sub *models.Subject) [][]Requirement {
 }
 
 func NewSubjectFromModel(model *models.Subject) *Subject {
+	stats, reviews := transformStats(model)
+
 	return &Subject{
 		Code:           model.Code,
 		CourseCode:     model.CourseCode,
@@ -48,7 +112,8 @@ func NewSubjectFromModel(model *models.Subject) *Subject {
 		AssignCredits:  model.AssignCredits,
 		TotalHours:     model.TotalHours,
 		Optional:       model.Optional,
-		Stats:          model.Stats,
+		Stats:          stats,
+		Reviews:        reviews,
 		Requirements:   transformRequirements(model),
 	}
 }
@@ -0,0 +1,25 @@
+package views
+
+import "github.com/Projeto-USPY/uspy-backend/entity/models"
+
+type SubjectSearchResult struct {
+	Code           string  `json:"code"`
+	CourseCode     string  `json:"course"`
+	Specialization string  `json:"specialization"`
+	Name           string  `json:"name"`
+	Semester       int     `json:"semester"`
+	Optional       bool    `json:"optional"`
+	Score          float64 `json:"score"`
+}
+
+func NewSubjectSearchResultFromModel(model *models.Subject, score float64) *SubjectSearchResult {
+	return &SubjectSearchResult{
+		Code:           model.Code,
+		CourseCode:     model.CourseCode,
+		Specialization: model.Specialization,
+		Name:           model.Name,
+		Semester:       model.Semester,
+		Optional:       model.Optional,
+		Score:          score,
+	}
+}
@@ -0,0 +1,55 @@
+package views
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformStatsCoarsenedSingleReview(t *testing.T) {
+	previous := config.Env
+	defer func() { config.Env = previous }()
+
+	config.Env.Mode = config.ModeProd
+	config.Env.Privacy = config.Privacy{GradesPolicy: "11:suppress", OfferingsPolicy: "5:suppress", ReviewsPolicy: "5:coarsen"}
+	require.NoError(t, config.Env.Privacy.Setup())
+
+	review := func(worthIt bool, difficulty float64) *models.Subject {
+		answer := "false"
+		if worthIt {
+			answer = "true"
+		}
+
+		positive := 0.0
+		if worthIt {
+			positive = 1
+		}
+
+		return &models.Subject{Stats: models.SubjectStats{
+			Total: 1,
+			Categories: map[string]models.CategoryStats{
+				"worth_it":   {Count: 1, Sum: positive, Histogram: map[string]int{answer: 1}},
+				"difficulty": {Count: 1, Sum: difficulty, Histogram: map[string]int{strconv.FormatFloat(difficulty, 'f', -1, 64): 1}},
+			},
+		}}
+	}
+
+	legacy, reviews := transformStats(review(true, 4))
+	otherLegacy, otherReviews := transformStats(review(false, 5))
+
+	// another single review with different answers looks the same, so neither can be recovered
+	assert.Equal(t, legacy, otherLegacy)
+	assert.Equal(t, reviews, otherReviews)
+
+	assert.Equal(t, 0, legacy["worth_it"])
+	for _, name := range []string{"worth_it", "difficulty"} {
+		stats := reviews[name]
+		assert.True(t, stats.Coarsened, name)
+		assert.Zero(t, stats.Count, name)
+		assert.Empty(t, stats.Histogram, name)
+	}
+}
@@ -0,0 +1,175 @@
+/* package i18n translates the messages of API errors */
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a BCP 47 tag of a language with a message catalog
+type Language string
+
+const (
+	PortugueseBR Language = "pt-BR"
+	English      Language = "en"
+)
+
+// Default is used when the client accepts no supported language
+const Default = PortugueseBR
+
+// ContextKey is the key of the request's Language, as set by middleware.Locale
+const ContextKey = "language"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalogs maps each supported language to its messages, keyed by error code or "field." + validator rule
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[Language]map[string]string {
+	entries, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := make(map[Language]map[string]string, len(entries))
+	for _, e := range entries {
+		data, err := locales.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic("i18n: invalid catalog " + e.Name() + ": " + err.Error())
+		}
+
+		catalogs[Language(strings.TrimSuffix(e.Name(), ".json"))] = messages
+	}
+
+	return catalogs
+}
+
+// Supported lists the languages with a catalog
+func Supported() []Language {
+	langs := make([]Language, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+// Parse matches a language tag to a supported language, ignoring case and region if there is no exact match
+//
+// e.g. "pt", "pt-PT" and "PT-br" are all PortugueseBR
+func Parse(tag string) (Language, bool) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", false
+	}
+
+	for lang := range catalogs {
+		if strings.EqualFold(string(lang), tag) {
+			return lang, true
+		}
+	}
+
+	base := strings.SplitN(tag, "-", 2)[0]
+	for _, lang := range Supported() {
+		if strings.EqualFold(strings.SplitN(string(lang), "-", 2)[0], base) {
+			return lang, true
+		}
+	}
+
+	return "", false
+}
+
+// Negotiate picks the supported language the client prefers from an Accept-Language header
+func Negotiate(acceptLanguage string) Language {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			tag = part[:i]
+			if v := strings.TrimSpace(part[i+1:]); strings.HasPrefix(v, "q=") {
+				parsed, err := strconv.ParseFloat(v[2:], 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+		}
+
+		if lang, ok := Parse(tag); ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+
+	return best
+}
+
+// FromContext returns the language of the request, or Default if middleware.Locale did not run
+//
+// gin.Context can be passed directly, since its values are also context values
+func FromContext(ctx context.Context) Language {
+	if lang, ok := ctx.Value(ContextKey).(Language); ok {
+		return lang
+	}
+
+	return Default
+}
+
+// Lookup returns the message for key, falling back to Default's catalog
+func Lookup(lang Language, key string) (string, bool) {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg, true
+	}
+
+	msg, ok := catalogs[Default][key]
+	return msg, ok
+}
+
+// Message returns the message for an error code, or fallback if no catalog has it
+func Message(lang Language, code, fallback string) string {
+	if msg, ok := Lookup(lang, code); ok {
+		return msg
+	}
+
+	return fallback
+}
+
+// FieldMessage explains why a field failed a validator rule
+//
+// length tells whether the field is a string or a collection, where min and max limit its length rather than its value
+func FieldMessage(lang Language, rule, param string, length bool) string {
+	switch rule {
+	case "gte":
+		rule = "min"
+	case "lte":
+		rule = "max"
+	case "oneof":
+		param = strings.ReplaceAll(param, " ", ", ")
+	}
+
+	msg, ok := "", false
+	if length && (rule == "min" || rule == "max") {
+		msg, ok = Lookup(lang, "field."+rule+"_length")
+	}
+
+	if !ok {
+		msg, ok = Lookup(lang, "field."+rule)
+	}
+
+	if !ok {
+		msg, _ = Lookup(lang, "field.invalid")
+	}
+
+	return strings.ReplaceAll(msg, "{param}", param)
+}
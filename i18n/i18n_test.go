@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for key := range catalogs[Default] {
+		for _, lang := range Supported() {
+			_, ok := catalogs[lang][key]
+			assert.True(t, ok, "%s has no message for %s", lang, key)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for tag, want := range map[string]Language{
+		"pt-BR": PortugueseBR,
+		"pt-br": PortugueseBR,
+		"pt":    PortugueseBR,
+		"pt-PT": PortugueseBR,
+		"en":    English,
+		"en-US": English,
+	} {
+		lang, ok := Parse(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, want, lang, tag)
+	}
+
+	for _, tag := range []string{"", "fr", "*"} {
+		_, ok := Parse(tag)
+		assert.False(t, ok, tag)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, Default, Negotiate(""))
+	assert.Equal(t, Default, Negotiate("fr-FR, de;q=0.8"))
+	assert.Equal(t, English, Negotiate("en-US,en;q=0.9"))
+	assert.Equal(t, English, Negotiate("fr, pt;q=0.5, en;q=0.8"))
+	assert.Equal(t, PortugueseBR, Negotiate("pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7"))
+	assert.Equal(t, PortugueseBR, Negotiate("en;q=0, pt;q=0.1"), "q=0 means not acceptable")
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "Wrong login or password.", Message(English, "invalid_credentials", ""))
+	assert.Equal(t, "Login ou senha incorretos.", Message(PortugueseBR, "invalid_credentials", ""))
+	assert.Equal(t, "fallback", Message(English, "unknown_code", "fallback"))
+
+	assert.Equal(t, English, FromContext(context.WithValue(context.Background(), ContextKey, English)))
+	assert.Equal(t, Default, FromContext(context.Background()))
+}
+
+func TestFieldMessage(t *testing.T) {
+	assert.Equal(t, "is required", FieldMessage(English, "required", "", false))
+	assert.Equal(t, "must be at least 1", FieldMessage(English, "gte", "1", false))
+	assert.Equal(t, "must have at most 10 characters", FieldMessage(English, "max", "10", true))
+	assert.Equal(t, "deve ser um dos valores: 1, -1", FieldMessage(PortugueseBR, "oneof", "1 -1", false))
+	assert.Equal(t, "deve ser um e-mail @usp.br", FieldMessage(PortugueseBR, "validateEmail", "", false))
+	assert.Equal(t, "is invalid", FieldMessage(English, "unknownRule", "", false))
+}
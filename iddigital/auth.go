@@ -2,19 +2,67 @@
 package iddigital
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/Projeto-USPY/uspy-backend/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// transport traces calls to uspdigital and propagates their trace context
+var transport = otelhttp.NewTransport(http.DefaultTransport)
+
+// requestIDHeader forwards the ID of the request that triggered a call, so both sides can be correlated
+const requestIDHeader = "X-Request-ID"
+
+// do sends a request to uspdigital on behalf of the request in ctx, logging and measuring how long it took
+//
+// operation labels the request in metrics.IddigitalRequestDuration
+func do(ctx context.Context, client *http.Client, operation, method, target string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if id := logger.RequestID(ctx); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+
+	fields := logger.Fields{"url": target, "latency_ms": float64(elapsed.Microseconds()) / 1000}
+	status := "error"
+	if err != nil {
+		fields["error"] = err
+	} else {
+		fields["status"] = resp.StatusCode
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	logger.FromContext(ctx).Debug("iddigital call", fields)
+	metrics.IddigitalRequestDuration.WithLabelValues(operation, status).Observe(elapsed.Seconds())
+
+	return resp, err
+}
+
 // GetCaptcha returns a new auth captcha for uspiddigital
-func GetCaptcha() (*http.Response, error) {
+func GetCaptcha(ctx context.Context) (*http.Response, error) {
 	captchaURL := "https://uspdigital.usp.br/iddigital/CriarImagemTuring"
 
-	resp, err := http.Get(captchaURL)
+	resp, err := do(ctx, &http.Client{Transport: transport}, "captcha", http.MethodGet, captchaURL, nil)
 
 	if err != nil {
 		return nil, fmt.Errorf("unable to get captcha: %v", err)
@@ -25,7 +73,7 @@ func GetCaptcha() (*http.Response, error) {
 
 // PostAuthCode submits the auth code along with the captcha to uspiddigital
 // The response object will contain the Grades PDF
-func PostAuthCode(auth string, captcha string, cookies []*http.Cookie) (*http.Response, error) {
+func PostAuthCode(ctx context.Context, auth string, captcha string, cookies []*http.Cookie) (*http.Response, error) {
 	fields := strings.Split(auth, "-")
 	postURL := "https://uspdigital.usp.br/iddigital/mostradocweb"
 
@@ -40,7 +88,7 @@ func PostAuthCode(auth string, captcha string, cookies []*http.Cookie) (*http.Re
 		return nil, fmt.Errorf("unable to create cookie jar: %v", err)
 	}
 
-	client := &http.Client{Jar: jar}
+	client := &http.Client{Jar: jar, Transport: transport}
 	data := url.Values{}
 	data.Set("chars", strings.TrimSpace(captcha))
 
@@ -48,7 +96,7 @@ func PostAuthCode(auth string, captcha string, cookies []*http.Cookie) (*http.Re
 		data.Set("codctl"+strconv.Itoa(i+1), v)
 	}
 
-	resp, err := client.PostForm(postURL, data)
+	resp, err := do(ctx, client, "transcript", http.MethodPost, postURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("unable to submit captcha and/or auth: %v", err)
 	}
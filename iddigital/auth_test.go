@@ -2,12 +2,13 @@
 package iddigital
 
 import (
+	"context"
 	"net/http/httputil"
 	"testing"
 )
 
 func TestGetCaptcha(t *testing.T) {
-	response, err := GetCaptcha()
+	response, err := GetCaptcha(context.Background())
 	defer response.Body.Close()
 
 	if err != nil {
@@ -2,6 +2,7 @@
 package iddigital
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -12,7 +13,10 @@ import (
 	"time"
 
 	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // iddigital.PDF represents the pdf file retrieved from uspdigital
@@ -34,8 +38,24 @@ type Transcript struct {
 	Specialization string `json:"specialization"`
 }
 
+// run feeds input to a command, traced as a child span of ctx, and returns its output
+func run(ctx context.Context, input []byte, name string, args ...string) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "exec "+name, attribute.Int("exec.input_bytes", len(input)))
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdin, _ := cmd.StdinPipe()
+	_, _ = stdin.Write(input)
+	_ = stdin.Close()
+	output, err := cmd.Output()
+
+	tracing.End(span, err)
+	return output, err
+}
+
 // NewPDF takes the Grades PDF response object and creates a new PDF object
-func NewPDF(r *http.Response) (pdf PDF) {
+//
+// The conversion subprocesses are killed if ctx is done
+func NewPDF(ctx context.Context, r *http.Response) (pdf PDF) {
 	defer func() {
 		if r := recover(); r != nil {
 			pdf.Body = ""
@@ -51,11 +71,7 @@ func NewPDF(r *http.Response) (pdf PDF) {
 	}
 
 	// transform PDF to string
-	parser := exec.Command("pdftotext", "-q", "-eol", "unix", "-enc", "UTF-8", "-layout", "-", "-")
-	stdin, _ := parser.StdinPipe()
-	_, _ = stdin.Write(bodyPDF)
-	_ = stdin.Close()
-	parsed, err := parser.Output()
+	parsed, err := run(ctx, bodyPDF, "pdftotext", "-q", "-eol", "unix", "-enc", "UTF-8", "-layout", "-", "-")
 
 	if err != nil {
 		panic(errors.New("error parsing pdf: " + err.Error()))
@@ -64,11 +80,7 @@ func NewPDF(r *http.Response) (pdf PDF) {
 	body := string(parsed)
 
 	// Get PDF CreationDate in ISO format
-	dataExtractor := exec.Command("pdfinfo", "-isodates", "-")
-	stdin, _ = dataExtractor.StdinPipe()
-	_, _ = stdin.Write(bodyPDF)
-	_ = stdin.Close()
-	meta, err := dataExtractor.Output()
+	meta, err := run(ctx, bodyPDF, "pdfinfo", "-isodates", "-")
 
 	if err != nil {
 		panic(errors.New("error getting pdf info: " + err.Error()))
@@ -101,6 +113,10 @@ func NewPDF(r *http.Response) (pdf PDF) {
 
 // Parse takes the (already read) PDF and parses it into a Transcript object
 func (pdf PDF) Parse(DB db.Env) (rec Transcript, err error) {
+	ctx, span := tracing.Start(DB.Ctx, "iddigital.Parse")
+	DB = DB.WithContext(ctx)
+	defer func() { tracing.End(span, err) }() // deferred first, so it sees the error set on recovery
+
 	defer func() {
 		if r := recover(); r != nil {
 			rec = Transcript{nil, "", "", "", ""}
@@ -125,7 +141,7 @@ func (pdf PDF) Parse(DB db.Env) (rec Transcript, err error) {
 	}
 
 	course, specialization := matches[1], matches[2]
-	snaps, err := DB.RestoreCollection("courses")
+	courses, _, err := db_utils.GetCourses(DB)
 	if err != nil {
 		panic(errors.New("could not fetch courses from firestore"))
 	}
@@ -166,9 +182,7 @@ func (pdf PDF) Parse(DB db.Env) (rec Transcript, err error) {
 			subSpecialization := ""
 
 			// determine subject course origin
-			for _, s := range snaps {
-				c := models.Course{}
-				_ = s.DataTo(&c)
+			for _, c := range courses {
 				_, exists := c.SubjectCodes[subCode]
 
 				if exists {
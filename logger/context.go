@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"regexp"
+)
+
+// ContextKey is the key of the request's logger in a gin.Context, whose values are also context values
+const ContextKey = "logger"
+
+type contextKey struct{}
+
+type requestIDKey struct{}
+
+// NewContext returns a copy of ctx carrying l
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger of a request, or Default if it has none
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+
+	if l, ok := ctx.Value(ContextKey).(*Logger); ok {
+		return l
+	}
+
+	return Default()
+}
+
+// WithRequestID returns a copy of ctx carrying the request's ID, so it can be sent to other services
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "" outside of requests
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// documentPath matches the resource prefix of Firestore document paths, which reveals the project and adds noise
+var documentPath = regexp.MustCompile(`projects/[^/\s]+/databases/[^/\s]+/documents/`)
+
+// Redact shortens Firestore document paths in error messages, e.g. to subjects/<hash>
+func Redact(msg string) string {
+	return documentPath.ReplaceAllString(msg, "")
+}
@@ -0,0 +1,189 @@
+/* package logger writes leveled, structured log entries, one JSON object per line */
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of an entry, entries below a logger's level are dropped
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the level's name, as understood by Cloud Logging
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// ParseLevel parses a level name, such as info or WARNING
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+const (
+	FormatJSON = "json" // one object per line, with severity and message keys read by Cloud Logging
+	FormatText = "text" // human readable, for local development
+)
+
+// Fields are attached to an entry, values must be encodable as JSON
+type Fields map[string]interface{}
+
+// Logger writes entries with its fields to an output
+//
+// Loggers derived with With share the output and its lock
+type Logger struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	level  Level
+	format string
+	fields Fields
+}
+
+// New creates a logger that writes entries of at least level to out
+func New(out io.Writer, level Level, format string) *Logger {
+	return &Logger{mu: &sync.Mutex{}, out: out, level: level, format: format}
+}
+
+var std = New(os.Stderr, LevelInfo, FormatJSON)
+
+// Default returns the logger used by the package functions and by requests without their own logger
+func Default() *Logger {
+	return std
+}
+
+// SetDefault replaces the default logger, it should be called before any requests are served
+func SetDefault(l *Logger) {
+	std = l
+}
+
+// With returns a logger that adds fields to every entry, overriding fields of the same name
+func (l *Logger) With(fields Fields) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	derived := *l
+	derived.fields = merged
+	return &derived
+}
+
+// Enabled tells whether entries of level are written
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+func (l *Logger) Debug(msg string, fields ...Fields) { l.log(LevelDebug, msg, fields) }
+func (l *Logger) Info(msg string, fields ...Fields)  { l.log(LevelInfo, msg, fields) }
+func (l *Logger) Warn(msg string, fields ...Fields)  { l.log(LevelWarn, msg, fields) }
+func (l *Logger) Error(msg string, fields ...Fields) { l.log(LevelError, msg, fields) }
+
+// Fatal writes an error entry and exits
+func (l *Logger) Fatal(msg string, fields ...Fields) {
+	l.log(LevelError, msg, fields)
+	os.Exit(1)
+}
+
+func (l *Logger) log(level Level, msg string, extra []Fields) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	entry := make(Fields, len(l.fields)+3)
+	for k, v := range l.fields {
+		entry[k] = v
+	}
+	for _, fields := range extra {
+		for k, v := range fields {
+			entry[k] = v
+		}
+	}
+
+	// errors do not encode as JSON by themselves
+	for k, v := range entry {
+		if err, ok := v.(error); ok {
+			entry[k] = err.Error()
+		}
+	}
+
+	now := time.Now()
+	var line []byte
+	if l.format == FormatText {
+		line = textLine(now, level, msg, entry)
+	} else {
+		entry["time"] = now.Format(time.RFC3339Nano)
+		entry["severity"] = level.String()
+		entry["message"] = msg
+
+		var err error
+		if line, err = json.Marshal(entry); err != nil {
+			line, _ = json.Marshal(Fields{"time": entry["time"], "severity": entry["severity"], "message": msg, "log_error": err.Error()})
+		}
+		line = append(line, '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(line)
+}
+
+// textLine formats an entry as "time LEVEL message key=value ...", with keys sorted
+func textLine(now time.Time, level Level, msg string, fields Fields) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %-7s %s", now.Format("2006/01/02 15:04:05"), level, msg)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func Debug(msg string, fields ...Fields) { std.log(LevelDebug, msg, fields) }
+func Info(msg string, fields ...Fields)  { std.log(LevelInfo, msg, fields) }
+func Warn(msg string, fields ...Fields)  { std.log(LevelWarn, msg, fields) }
+func Error(msg string, fields ...Fields) { std.log(LevelError, msg, fields) }
+func Fatal(msg string, fields ...Fields) { std.Fatal(msg, fields...) }
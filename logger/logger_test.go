@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, out *bytes.Buffer) []Fields {
+	entries := make([]Fields, 0)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var entry Fields
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := New(&out, LevelInfo, FormatJSON).With(Fields{"request_id": "abc"})
+
+	l.Debug("dropped")
+	l.Info("served", Fields{"status": 200})
+	l.With(Fields{"user": "hash"}).Error("failed", Fields{"error": errors.New("boom")})
+
+	entries := decodeLines(t, &out)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "INFO", entries[0]["severity"])
+	assert.Equal(t, "served", entries[0]["message"])
+	assert.Equal(t, "abc", entries[0]["request_id"])
+	assert.EqualValues(t, 200, entries[0]["status"])
+	assert.NotEmpty(t, entries[0]["time"])
+	assert.NotContains(t, entries[0], "user", "derived loggers do not change their parent")
+
+	assert.Equal(t, "ERROR", entries[1]["severity"])
+	assert.Equal(t, "hash", entries[1]["user"])
+	assert.Equal(t, "boom", entries[1]["error"])
+}
+
+func TestTextFormat(t *testing.T) {
+	var out bytes.Buffer
+	New(&out, LevelDebug, FormatText).Warn("slow", Fields{"b": 2, "a": 1})
+	assert.Regexp(t, `^\S+ \S+ WARNING slow a=1 b=2\n$`, out.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warning": LevelWarn, "warn": LevelWarn, "error": LevelError} {
+		level, err := ParseLevel(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, level, name)
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestContext(t *testing.T) {
+	l := New(&bytes.Buffer{}, LevelInfo, FormatJSON)
+	assert.Same(t, l, FromContext(NewContext(context.Background(), l)))
+	assert.Same(t, Default(), FromContext(context.Background()))
+
+	assert.Equal(t, "abc", RequestID(WithRequestID(context.Background(), "abc")))
+	assert.Empty(t, RequestID(context.Background()))
+}
+
+func TestRedact(t *testing.T) {
+	err := "rpc error: code = NotFound desc = \"projects/uspy/databases/(default)/documents/users/abc/final_scores/SSC0101\" not found"
+	assert.Equal(t, "rpc error: code = NotFound desc = \"users/abc/final_scores/SSC0101\" not found", Redact(err))
+}
@@ -2,24 +2,50 @@
 package main
 
 import (
-	"log"
+	"context"
+	"os/signal"
+	"syscall"
 
 	"github.com/Projeto-USPY/uspy-backend/config"
 	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/logger"
 	"github.com/Projeto-USPY/uspy-backend/server"
+	"github.com/Projeto-USPY/uspy-backend/tracing"
 )
 
 func init() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	config.Setup()
 }
 
 func main() {
-	DB := db.SetupDB()
+	// cloud run stops instances with SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Env.Tracing)
+	if err != nil {
+		logger.Fatal("could not setup tracing", logger.Fields{"error": err})
+	}
+
+	// background workers stop as soon as the signal arrives, requests use their own contexts
+	DB := db.SetupDB().WithContext(ctx)
 	r, err := server.SetupRouter(DB)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatal("could not setup router", logger.Fields{"error": err})
+	}
+
+	if err := server.Run(ctx, config.Env.Domain+":"+config.Env.Port, r); err != nil {
+		logger.Error("server stopped with error", logger.Fields{"error": err})
+	}
+
+	if err := DB.Client.Close(); err != nil {
+		logger.Warn("could not close firestore client", logger.Fields{"error": err})
+	}
+
+	// spans of the last requests are still buffered
+	if err := shutdownTracing(context.Background()); err != nil {
+		logger.Warn("could not flush traces", logger.Fields{"error": err})
 	}
 
-	_ = r.Run(config.Env.Domain + ":" + config.Env.Port)
+	logger.Info("server stopped")
 }
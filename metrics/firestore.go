@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "google.golang.org/genproto/googleapis/firestore/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// FirestoreDialOptions instrument the gRPC connection of a Firestore client
+//
+// The emulator client dials its own connection, so operations against the emulator are not measured
+func FirestoreDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(firestoreUnaryInterceptor),
+		grpc.WithChainStreamInterceptor(firestoreStreamInterceptor),
+	}
+}
+
+// CollectionOf returns the collection path of a document path with the document IDs left out
+//
+// e.g. "projects/p/databases/(default)/documents/users/abc/final_scores/x" is "users/final_scores"
+func CollectionOf(name string) string {
+	if i := strings.Index(name, "/documents/"); i >= 0 {
+		name = name[i+len("/documents/"):]
+	} else if strings.HasSuffix(name, "/documents") {
+		return ""
+	}
+
+	segments := strings.Split(name, "/")
+	collections := make([]string, 0, (len(segments)+1)/2)
+	for i := 0; i < len(segments); i += 2 {
+		collections = append(collections, segments[i])
+	}
+
+	return strings.Join(collections, "/")
+}
+
+// FirestoreCollections lists the collections a Firestore request touches, with document IDs left out
+func FirestoreCollections(req interface{}) []string {
+	var names, queried []string
+	switch r := req.(type) {
+	case *pb.BatchGetDocumentsRequest:
+		names = r.GetDocuments()
+	case *pb.GetDocumentRequest:
+		names = []string{r.GetName()}
+	case *pb.RunQueryRequest:
+		parent := CollectionOf(r.GetParent())
+		for _, from := range r.GetStructuredQuery().GetFrom() {
+			queried = append(queried, path.Join(parent, from.GetCollectionId()))
+		}
+	case *pb.CommitRequest:
+		for _, w := range r.GetWrites() {
+			switch {
+			case w.GetUpdate() != nil:
+				names = append(names, w.GetUpdate().GetName())
+			case w.GetDelete() != "":
+				names = append(names, w.GetDelete())
+			case w.GetTransform() != nil:
+				names = append(names, w.GetTransform().GetDocument())
+			}
+		}
+	}
+
+	for _, name := range names {
+		queried = append(queried, CollectionOf(name))
+	}
+
+	seen := make(map[string]bool, len(queried))
+	collections := make([]string, 0, len(queried))
+	for _, c := range queried {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			collections = append(collections, c)
+		}
+	}
+
+	sort.Strings(collections)
+	return collections
+}
+
+// observeFirestore records one operation for each collection it touched
+func observeFirestore(method string, collections []string, start time.Time, err error) {
+	code := status.Code(err).String()
+	elapsed := time.Since(start).Seconds()
+	for _, c := range collections {
+		FirestoreOperations.WithLabelValues(c, method, code).Inc()
+		FirestoreOperationDuration.WithLabelValues(c, method).Observe(elapsed)
+	}
+}
+
+func firestoreUnaryInterceptor(ctx context.Context, fullMethod string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if begin, ok := req.(*pb.BeginTransactionRequest); ok && len(begin.GetOptions().GetReadWrite().GetRetryTransaction()) > 0 {
+		FirestoreTransactionRetries.Inc()
+	}
+
+	start := time.Now()
+	err := invoker(ctx, fullMethod, req, reply, cc, opts...)
+	observeFirestore(path.Base(fullMethod), FirestoreCollections(req), start, err)
+	return err
+}
+
+func firestoreStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, fullMethod string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	stream, err := streamer(ctx, desc, cc, fullMethod, opts...)
+	method := path.Base(fullMethod)
+
+	// listeners stay open for as long as the server runs, their duration means nothing
+	if err != nil || desc.ClientStreams {
+		return stream, err
+	}
+
+	return &observedStream{ClientStream: stream, method: method, start: start}, nil
+}
+
+// observedStream records a server streaming operation once its last message is received
+type observedStream struct {
+	grpc.ClientStream
+	method      string
+	start       time.Time
+	collections []string
+	once        sync.Once
+}
+
+func (s *observedStream) SendMsg(m interface{}) error {
+	s.collections = FirestoreCollections(m)
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *observedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				observeFirestore(s.method, s.collections, s.start, nil)
+			} else {
+				observeFirestore(s.method, s.collections, s.start, err)
+			}
+		})
+	}
+
+	return err
+}
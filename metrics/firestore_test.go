@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	pb "google.golang.org/genproto/googleapis/firestore/v1"
+	"google.golang.org/grpc"
+)
+
+const root = "projects/p/databases/(default)/documents"
+
+func TestCollectionOf(t *testing.T) {
+	assert.Equal(t, "subjects", CollectionOf(root+"/subjects/abc"))
+	assert.Equal(t, "users/final_scores", CollectionOf(root+"/users/abc/final_scores/SSC0101"))
+	assert.Equal(t, "users", CollectionOf(root+"/users/abc"))
+	assert.Equal(t, "", CollectionOf(root))
+}
+
+func TestRequestCollections(t *testing.T) {
+	assert.Equal(t, []string{"subjects", "users"}, FirestoreCollections(&pb.BatchGetDocumentsRequest{
+		Documents: []string{root + "/users/a", root + "/subjects/b", root + "/users/c"},
+	}))
+
+	assert.Equal(t, []string{"users/final_scores"}, FirestoreCollections(&pb.RunQueryRequest{
+		Parent:    root + "/users/abc",
+		QueryType: &pb.RunQueryRequest_StructuredQuery{StructuredQuery: &pb.StructuredQuery{From: []*pb.StructuredQuery_CollectionSelector{{CollectionId: "final_scores"}}}},
+	}))
+
+	assert.Equal(t, []string{"subjects/reviews", "users"}, FirestoreCollections(&pb.CommitRequest{Writes: []*pb.Write{
+		{Operation: &pb.Write_Update{Update: &pb.Document{Name: root + "/users/a"}}},
+		{Operation: &pb.Write_Delete{Delete: root + "/subjects/b/reviews/c"}},
+	}}))
+
+	assert.Empty(t, FirestoreCollections(&pb.RollbackRequest{}))
+}
+
+func TestFirestoreUnaryInterceptor(t *testing.T) {
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+
+	retries := testutil.ToFloat64(FirestoreTransactionRetries)
+	begin := &pb.BeginTransactionRequest{Options: &pb.TransactionOptions{Mode: &pb.TransactionOptions_ReadWrite_{
+		ReadWrite: &pb.TransactionOptions_ReadWrite{RetryTransaction: []byte("tx")},
+	}}}
+	assert.NoError(t, firestoreUnaryInterceptor(context.Background(), "/google.firestore.v1.Firestore/BeginTransaction", begin, nil, nil, invoker))
+	assert.Equal(t, retries+1, testutil.ToFloat64(FirestoreTransactionRetries))
+
+	commits := testutil.ToFloat64(FirestoreOperations.WithLabelValues("users", "Commit", "OK"))
+	commit := &pb.CommitRequest{Writes: []*pb.Write{{Operation: &pb.Write_Update{Update: &pb.Document{Name: root + "/users/a"}}}}}
+	assert.NoError(t, firestoreUnaryInterceptor(context.Background(), "/google.firestore.v1.Firestore/Commit", commit, nil, nil, invoker))
+	assert.Equal(t, commits+1, testutil.ToFloat64(FirestoreOperations.WithLabelValues("users", "Commit", "OK")))
+}
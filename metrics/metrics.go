@@ -0,0 +1,107 @@
+/* package metrics defines the Prometheus metrics served at /metrics */
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric of this package, along with Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+// Signup outcomes, see SignupOutcomes
+const (
+	SignupSuccess        = "success"
+	SignupCaptchaFailure = "captcha_failure" // uspdigital did not return a transcript, the captcha or access key was wrong
+	SignupPDFTooOld      = "pdf_too_old"
+	SignupParseError     = "parse_error"
+	SignupDuplicateUser  = "duplicate_user"
+	SignupDuplicateEmail = "duplicate_email"
+	SignupIddigitalError = "iddigital_error" // uspdigital could not be reached
+	SignupError          = "error"
+)
+
+var (
+	// HTTPRequestDuration observes each request, routes are labeled by template so their cardinality is bounded
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "uspy",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// SignupOutcomes counts signup attempts by how they ended
+	SignupOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "uspy",
+		Name:      "signups_total",
+		Help:      "Signup attempts, by outcome.",
+	}, []string{"outcome"})
+
+	// IddigitalRequestDuration observes calls to uspdigital, status is "error" if no response was received
+	IddigitalRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "uspy",
+		Subsystem: "iddigital",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken by uspdigital requests, by operation and status.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20},
+	}, []string{"operation", "status"})
+
+	// FirestoreOperations counts Firestore RPCs by the collections they touch and their gRPC code
+	FirestoreOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "uspy",
+		Subsystem: "firestore",
+		Name:      "operations_total",
+		Help:      "Firestore operations, by collection, method and gRPC code.",
+	}, []string{"collection", "method", "code"})
+
+	// FirestoreOperationDuration observes Firestore RPCs, streamed reads are timed until their last result
+	FirestoreOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "uspy",
+		Subsystem: "firestore",
+		Name:      "operation_duration_seconds",
+		Help:      "Time taken by Firestore operations, by collection and method.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"collection", "method"})
+
+	// FirestoreTransactionRetries counts transactions that were attempted again after contention
+	FirestoreTransactionRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "uspy",
+		Subsystem: "firestore",
+		Name:      "transaction_retries_total",
+		Help:      "Firestore transactions retried after a conflict.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		SignupOutcomes,
+		IddigitalRequestDuration,
+		FirestoreOperations,
+		FirestoreOperationDuration,
+		FirestoreTransactionRetries,
+	)
+
+	// every outcome is exported from the start, so rates can be computed before the first failure
+	for _, outcome := range []string{SignupSuccess, SignupCaptchaFailure, SignupPDFTooOld, SignupParseError, SignupDuplicateUser, SignupDuplicateEmail, SignupIddigitalError, SignupError} {
+		SignupOutcomes.WithLabelValues(outcome)
+	}
+}
+
+// Signup counts a signup attempt
+func Signup(outcome string) {
+	SignupOutcomes.WithLabelValues(outcome).Inc()
+}
+
+// Handler serves the metrics in the Prometheus text format
+//
+// Compression is left to middleware.Compress
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{DisableCompression: true})
+}
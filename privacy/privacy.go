@@ -0,0 +1,80 @@
+/* package privacy decides how aggregate statistics may be shown without exposing the students behind them */
+package privacy
+
+import (
+	"github.com/Projeto-USPY/uspy-backend/config"
+)
+
+// Outcome is the result of checking a statistic against its privacy policy
+type Outcome struct {
+	Suppressed bool
+	Coarsened  bool
+}
+
+// Visible reports whether the statistic may be shown unchanged
+func (o Outcome) Visible() bool {
+	return !o.Suppressed && !o.Coarsened
+}
+
+// Enforced reports whether privacy policies are currently applied
+func Enforced() bool {
+	return config.Env.IsProd() || config.Env.Privacy.Enforce
+}
+
+// Check tells what must be done to a statistic computed from the given number of contributors
+//
+// Statistics with no contributors have nothing to hide, so they are always visible
+func Check(stat string, contributors int) Outcome {
+	policy := config.Env.Privacy.Policy(stat)
+	if !Enforced() || contributors == 0 || contributors >= policy.K {
+		return Outcome{}
+	}
+
+	return Hidden(stat)
+}
+
+// Hidden returns the outcome of a statistic that must not be shown as is, whatever its number of contributors
+func Hidden(stat string) Outcome {
+	if config.Env.Privacy.Policy(stat).Action == config.PrivacyCoarsen {
+		return Outcome{Coarsened: true}
+	}
+
+	return Outcome{Suppressed: true}
+}
+
+// CheckSlices checks statistics that split a total into parts, such as the grades given in each year of a subject
+//
+// Checking each slice on its own is not enough when the total is also shown: a single hidden slice could be recovered
+// by subtracting the visible slices from it. So when exactly one slice is hidden, the smallest visible slice with
+// contributors is hidden too (complementary suppression)
+func CheckSlices(stat string, contributors []int) []Outcome {
+	outcomes := make([]Outcome, len(contributors))
+	hidden, smallest := 0, -1
+	for i, n := range contributors {
+		outcomes[i] = Check(stat, n)
+		if !outcomes[i].Visible() {
+			hidden++
+		} else if n > 0 && (smallest == -1 || n < contributors[smallest]) {
+			smallest = i
+		}
+	}
+
+	if hidden == 1 && smallest != -1 {
+		outcomes[smallest] = Hidden(stat)
+	}
+
+	return outcomes
+}
+
+// Coarsen hides an average computed from few contributors, returning the middle of the half of [min, max] it falls in
+//
+// Each half of a scale holds several possible answers, so not even a single contributor's answer can be recovered.
+// Rates of yes/no answers cannot be coarsened this way (with one contributor they are 0 or 1), they must be dropped
+func Coarsen(value, min, max float64) float64 {
+	middle := (min + max) / 2
+	if value < middle {
+		return (min + middle) / 2
+	}
+
+	return (middle + max) / 2
+}
@@ -0,0 +1,90 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	previous := config.Env
+	defer func() { config.Env = previous }()
+
+	config.Env.Privacy = config.Privacy{
+		GradesPolicy:    "11:suppress",
+		OfferingsPolicy: "5:suppress",
+		ReviewsPolicy:   "5:coarsen",
+	}
+	require.NoError(t, config.Env.Privacy.Setup())
+
+	// not enforced outside production
+	config.Env.Mode = "local"
+	assert.True(t, Check(config.StatGrades, 3).Visible())
+
+	config.Env.Privacy.Enforce = true
+	assert.Equal(t, Outcome{Suppressed: true}, Check(config.StatGrades, 10))
+	assert.True(t, Check(config.StatGrades, 11).Visible())
+	assert.True(t, Check(config.StatGrades, 0).Visible(), "nothing to hide without contributors")
+	assert.Equal(t, Outcome{Coarsened: true}, Check(config.StatReviews, 4))
+	assert.True(t, Check(config.StatOfferings, 5).Visible())
+
+	config.Env.Privacy.Enforce = false
+	config.Env.Mode = "prod"
+	assert.Equal(t, Outcome{Suppressed: true}, Check(config.StatOfferings, 1))
+}
+
+func TestCheckSlices(t *testing.T) {
+	previous := config.Env
+	defer func() { config.Env = previous }()
+
+	config.Env.Mode = config.ModeProd
+	config.Env.Privacy = config.Privacy{GradesPolicy: "11:suppress", OfferingsPolicy: "5:suppress", ReviewsPolicy: "5:coarsen"}
+	require.NoError(t, config.Env.Privacy.Setup())
+
+	hidden := func(outcomes []Outcome) []int {
+		indexes := make([]int, 0)
+		for i, o := range outcomes {
+			if !o.Visible() {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	// a single small slice takes the smallest visible one with it
+	assert.Equal(t, []int{0, 2}, hidden(CheckSlices(config.StatGrades, []int{3, 30, 20, 0})))
+
+	// two hidden slices already protect each other
+	assert.Equal(t, []int{0, 1}, hidden(CheckSlices(config.StatGrades, []int{3, 4, 20})))
+
+	// nothing else to hide
+	assert.Equal(t, []int{0}, hidden(CheckSlices(config.StatGrades, []int{3, 0})))
+	assert.Empty(t, hidden(CheckSlices(config.StatGrades, []int{11, 12})))
+
+	// the complementary slice gets the statistic's action
+	assert.Equal(t, []Outcome{{Coarsened: true}, {Coarsened: true}}, CheckSlices(config.StatReviews, []int{1, 9}))
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	for _, policy := range []string{"", "10", "ten:suppress", "-1:suppress", "10:hide"} {
+		p := config.Privacy{GradesPolicy: policy, OfferingsPolicy: "1:suppress", ReviewsPolicy: "1:suppress"}
+		assert.Error(t, p.Setup(), policy)
+	}
+}
+
+func TestCoarsen(t *testing.T) {
+	assert.Equal(t, 2.0, Coarsen(1, 1, 5))
+	assert.Equal(t, 4.0, Coarsen(3, 1, 5))
+	assert.Equal(t, 7.5, Coarsen(10, 0, 10))
+
+	// with a single contributor the average is their answer, which must share its coarsened value with another answer
+	for answer := 1.0; answer <= 5; answer++ {
+		shared := false
+		for other := 1.0; other <= 5; other++ {
+			shared = shared || (other != answer && Coarsen(other, 1, 5) == Coarsen(answer, 1, 5))
+		}
+		assert.True(t, shared, "answer %v can be recovered", answer)
+	}
+}
@@ -0,0 +1,275 @@
+/* package search contains an in-memory full-text index over the subjects catalog */
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+)
+
+// Field identifies which part of a subject a term was found in
+type Field int
+
+const (
+	FieldCode Field = iota
+	FieldName
+	FieldDescription
+)
+
+// fieldWeights defines how much a match in each field is worth
+var fieldWeights = map[Field]float64{
+	FieldCode:        10.0,
+	FieldName:        4.0,
+	FieldDescription: 1.0,
+}
+
+// Match qualities, used to rank exact matches above prefix and fuzzy ones
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.75
+	fuzzyMatch  = 0.5
+)
+
+type posting struct {
+	doc   int
+	field Field
+	count int
+}
+
+// Filter restricts search results to a course, specialization and/or optional status
+//
+// Empty values are ignored
+type Filter struct {
+	Course         string
+	Specialization string
+	Optional       *bool
+}
+
+func (f Filter) accepts(sub *models.Subject) bool {
+	if f.Course != "" && sub.CourseCode != f.Course {
+		return false
+	}
+
+	if f.Specialization != "" && sub.Specialization != f.Specialization {
+		return false
+	}
+
+	if f.Optional != nil && sub.Optional != *f.Optional {
+		return false
+	}
+
+	return true
+}
+
+// Result is a subject matched by a query, along with its relevance score
+type Result struct {
+	Subject *models.Subject
+	Score   float64
+}
+
+// Index is a thread-safe inverted index over subject codes, names and descriptions
+//
+// It is meant to be built once and rebuilt in the background, see Index.Watch
+type Index struct {
+	mu       sync.RWMutex
+	subjects []models.Subject
+	postings map[string][]posting
+	terms    []string // sorted vocabulary, used for prefix and fuzzy lookups
+	builtAt  time.Time
+
+	// staging holds the latest known state of the subjects collection until the next rebuild
+	stagingMu sync.Mutex
+	staging   map[string]models.Subject
+	timer     *time.Timer
+
+	// OnChange, if set, is called by Watch with the IDs of the subjects that changed
+	OnChange func(ids []string)
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string][]posting),
+		staging:  make(map[string]models.Subject),
+	}
+}
+
+// Ready reports whether the index has been built at least once
+func (idx *Index) Ready() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return !idx.builtAt.IsZero()
+}
+
+// Len returns the number of indexed subjects
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.subjects)
+}
+
+// BuiltAt returns when the index was last rebuilt
+func (idx *Index) BuiltAt() time.Time {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.builtAt
+}
+
+// Build replaces the index contents with the given subjects
+func (idx *Index) Build(subjects []models.Subject) {
+	postings := make(map[string][]posting)
+
+	add := func(doc int, field Field, tokens []string) {
+		counts := make(map[string]int)
+		for _, t := range tokens {
+			counts[t]++
+		}
+
+		for t, c := range counts {
+			postings[t] = append(postings[t], posting{doc: doc, field: field, count: c})
+		}
+	}
+
+	for i := range subjects {
+		add(i, FieldCode, codeTokens(subjects[i].Code))
+		add(i, FieldName, Tokenize(subjects[i].Name))
+		add(i, FieldDescription, Tokenize(subjects[i].Description))
+	}
+
+	terms := make([]string, 0, len(postings))
+	for t := range postings {
+		terms = append(terms, t)
+	}
+	sort.Strings(terms)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.subjects = subjects
+	idx.postings = postings
+	idx.terms = terms
+	idx.builtAt = time.Now()
+}
+
+// expand finds every indexed term that matches the query term, along with the quality of the match
+func (idx *Index) expand(term string) map[string]float64 {
+	matches := make(map[string]float64)
+
+	if _, ok := idx.postings[term]; ok {
+		matches[term] = exactMatch
+	}
+
+	// prefix matches: the vocabulary is sorted so they are contiguous
+	if len([]rune(term)) >= 2 {
+		start := sort.SearchStrings(idx.terms, term)
+		for i := start; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], term); i++ {
+			if _, ok := matches[idx.terms[i]]; !ok {
+				matches[idx.terms[i]] = prefixMatch
+			}
+		}
+	}
+
+	// fuzzy matches: tolerate typos depending on the term length
+	if edits := maxEdits(term); edits > 0 {
+		for _, t := range idx.terms {
+			if _, ok := matches[t]; ok {
+				continue
+			}
+
+			if d := levenshtein(term, t, edits); d <= edits {
+				matches[t] = fuzzyMatch / float64(d)
+			}
+		}
+	}
+
+	return matches
+}
+
+// Search looks up the query in the index and returns up to limit results, ordered by relevance
+//
+// Every query term contributes to the score with the best match it had in each field.
+// Subjects that match more of the query terms are always ranked first.
+func (idx *Index) Search(query string, filter Filter, limit int) []Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return []Result{}
+	}
+
+	type hit struct {
+		score   float64
+		matched int
+	}
+
+	hits := make(map[int]*hit)
+	for _, term := range terms {
+		best := make(map[int]map[Field]float64) // best quality for this term per document and field
+
+		for indexed, quality := range idx.expand(term) {
+			for _, p := range idx.postings[indexed] {
+				if !filter.accepts(&idx.subjects[p.doc]) {
+					continue
+				}
+
+				if best[p.doc] == nil {
+					best[p.doc] = make(map[Field]float64)
+				}
+
+				value := quality * (1.0 + math.Log(float64(p.count)))
+				if value > best[p.doc][p.field] {
+					best[p.doc][p.field] = value
+				}
+			}
+		}
+
+		for doc, fields := range best {
+			h, ok := hits[doc]
+			if !ok {
+				h = &hit{}
+				hits[doc] = h
+			}
+
+			for field, value := range fields {
+				h.score += fieldWeights[field] * value
+			}
+			h.matched++
+		}
+	}
+
+	results := make([]Result, 0, len(hits))
+	matched := make(map[*models.Subject]int, len(hits))
+	for doc, h := range hits {
+		sub := &idx.subjects[doc]
+		results = append(results, Result{Subject: sub, Score: h.score})
+		matched[sub] = h.matched
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		mi, mj := matched[results[i].Subject], matched[results[j].Subject]
+		if mi != mj {
+			return mi > mj
+		}
+
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		if results[i].Subject.Code != results[j].Subject.Code {
+			return results[i].Subject.Code < results[j].Subject.Code
+		}
+
+		return results[i].Subject.CourseCode < results[j].Subject.CourseCode
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+)
+
+var testSubjects = []models.Subject{
+	{
+		Code:           "SCC0222",
+		CourseCode:     "55041",
+		Specialization: "0",
+		Name:           "Laboratório de Introdução à Ciência de Computação I",
+		Description:    "Implementar em laboratório as técnicas de programação apresentadas em Introdução à Ciência da Computação I.",
+		Optional:       true,
+	},
+	{
+		Code:           "SCC0217",
+		CourseCode:     "55041",
+		Specialization: "0",
+		Name:           "Linguagens de Programação e Compiladores",
+		Description:    "Dar ao aluno as noções básicas sobre linguagens de programação e técnicas de construção de compiladores.",
+	},
+	{
+		Code:           "SCC0230",
+		CourseCode:     "55090",
+		Specialization: "0",
+		Name:           "Inteligência Artificial",
+		Description:    "Apresentar ao aluno as idéias fundamentais da Inteligência Artificial.",
+	},
+}
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Build(testSubjects)
+	return idx
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("Introdução à Computação"); got != "introducao a computacao" {
+		t.Fatalf("unexpected normalization: %q", got)
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	if got := strings.Join(Prefixes("João da Silva"), " "); got != "joa joao sil silv silva" {
+		t.Fatalf("unexpected prefixes: %q", got)
+	}
+
+	if got := strings.Join(Prefixes("Li"), " "); got != "li" {
+		t.Fatalf("short words should be kept whole: %q", got)
+	}
+}
+
+func TestSearchByCode(t *testing.T) {
+	results := newTestIndex().Search("scc0217", Filter{}, 10)
+	if len(results) != 1 || results[0].Subject.Code != "SCC0217" {
+		t.Fatalf("expected SCC0217 only, got %v", results)
+	}
+}
+
+func TestSearchIsAccentInsensitive(t *testing.T) {
+	results := newTestIndex().Search("inteligencia", Filter{}, 10)
+	if len(results) == 0 || results[0].Subject.Code != "SCC0230" {
+		t.Fatalf("expected SCC0230 first, got %v", results)
+	}
+}
+
+func TestSearchToleratesTypos(t *testing.T) {
+	results := newTestIndex().Search("compiladroes", Filter{}, 10)
+	if len(results) == 0 || results[0].Subject.Code != "SCC0217" {
+		t.Fatalf("expected SCC0217 first, got %v", results)
+	}
+}
+
+func TestSearchRanksNameAboveDescription(t *testing.T) {
+	results := newTestIndex().Search("programação", Filter{}, 10)
+	if len(results) != 2 || results[0].Subject.Code != "SCC0217" {
+		t.Fatalf("expected SCC0217 to be ranked first, got %v", results)
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	idx := newTestIndex()
+	optional := true
+
+	if results := idx.Search("scc", Filter{Course: "55041"}, 10); len(results) != 2 {
+		t.Fatalf("expected 2 subjects from course 55041, got %d", len(results))
+	}
+
+	results := idx.Search("scc", Filter{Optional: &optional}, 10)
+	if len(results) != 1 || results[0].Subject.Code != "SCC0222" {
+		t.Fatalf("expected only the optional subject, got %v", results)
+	}
+
+	if results := idx.Search("scc", Filter{}, 1); len(results) != 1 {
+		t.Fatalf("expected limit to be respected, got %d results", len(results))
+	}
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stopwords are common portuguese words that carry no meaning for subject lookups
+var stopwords = map[string]struct{}{
+	"a": {}, "o": {}, "e": {}, "as": {}, "os": {}, "de": {}, "da": {}, "do": {}, "das": {}, "dos": {},
+	"em": {}, "na": {}, "no": {}, "nas": {}, "nos": {}, "para": {}, "por": {}, "com": {}, "um": {},
+	"uma": {}, "ao": {}, "aos": {}, "que": {}, "se": {}, "ou": {},
+}
+
+// Normalize lowercases the text and strips its accents, so "Computação" becomes "computacao"
+func Normalize(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		result = text
+	}
+
+	return strings.ToLower(result)
+}
+
+// Tokenize normalizes the text and splits it into searchable terms, ignoring stopwords
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(Normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if _, ok := stopwords[f]; ok {
+			continue
+		}
+
+		tokens = append(tokens, f)
+	}
+
+	return tokens
+}
+
+// MinPrefix is the shortest prefix returned by Prefixes
+const MinPrefix = 3
+
+// Prefixes returns every prefix of at least MinPrefix letters of each term in the text, without repetitions
+//
+// They are stored in documents so that firestore array-contains queries can match partially typed words
+func Prefixes(text string) []string {
+	seen := make(map[string]bool)
+	prefixes := make([]string, 0)
+
+	for _, t := range Tokenize(text) {
+		runes := []rune(t)
+		for i := minInt(MinPrefix, len(runes)); i <= len(runes); i++ {
+			if p := string(runes[:i]); !seen[p] {
+				seen[p] = true
+				prefixes = append(prefixes, p)
+			}
+		}
+	}
+
+	return prefixes
+}
+
+// codeTokens splits a subject code such as SCC0222 into the whole code, its prefix and its number
+//
+// This lets users find subjects by typing only "scc" or "0222"
+func codeTokens(code string) []string {
+	code = Normalize(code)
+	tokens := []string{code}
+
+	split := strings.IndexFunc(code, unicode.IsDigit)
+	if split > 0 {
+		tokens = append(tokens, code[:split], code[split:])
+	}
+
+	return tokens
+}
+
+// levenshtein calculates the edit distance between a and b, giving up once it is greater than max
+func levenshtein(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+
+		if rowMin > max {
+			return max + 1
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// maxEdits returns how many typos are tolerated for a term, depending on its length
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+)
+
+// RebuildDelay is how long the index waits for more changes before rebuilding
+//
+// The scraper updates thousands of subjects at once, so rebuilding on every change would be wasteful
+var RebuildDelay = 5 * time.Second
+
+// Load builds the index with every subject currently stored in the database
+func (idx *Index) Load(DB db.Env) error {
+	subjects, err := db.ListByID[models.Subject](DB, "subjects", db.Query{})
+	if err != nil {
+		return err
+	}
+
+	idx.stagingMu.Lock()
+	for id, sub := range subjects {
+		idx.staging[id] = *sub
+	}
+	staged := idx.stagedSubjects()
+	idx.stagingMu.Unlock()
+
+	idx.Build(staged)
+	return nil
+}
+
+// Watch listens to changes in the subjects collection and rebuilds the index accordingly
+//
+// It blocks until ctx is cancelled, so it should be run in its own goroutine
+func (idx *Index) Watch(ctx context.Context, DB db.Env) {
+	it := DB.Client.Collection("subjects").Snapshots(ctx)
+	defer it.Stop()
+
+	for first := true; ; first = false {
+		snap, err := it.Next()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("stopped watching subjects for search index", logger.Fields{"error": err})
+			}
+			return
+		}
+
+		idx.apply(snap.Changes)
+
+		// the first snapshot has every subject, it says nothing about changes
+		if !first && idx.OnChange != nil && len(snap.Changes) > 0 {
+			ids := make([]string, 0, len(snap.Changes))
+			for _, c := range snap.Changes {
+				ids = append(ids, c.Doc.Ref.ID)
+			}
+
+			idx.OnChange(ids)
+		}
+	}
+}
+
+// apply stages the document changes and schedules a rebuild
+func (idx *Index) apply(changes []firestore.DocumentChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	idx.stagingMu.Lock()
+	defer idx.stagingMu.Unlock()
+
+	for _, c := range changes {
+		if c.Kind == firestore.DocumentRemoved {
+			delete(idx.staging, c.Doc.Ref.ID)
+			continue
+		}
+
+		var sub models.Subject
+		if err := c.Doc.DataTo(&sub); err != nil {
+			logger.Warn("could not bind subject to search index", logger.Fields{"subject": c.Doc.Ref.ID, "error": err})
+			continue
+		}
+
+		idx.staging[c.Doc.Ref.ID] = sub
+	}
+
+	if idx.timer == nil {
+		idx.timer = time.AfterFunc(RebuildDelay, idx.rebuild)
+	}
+}
+
+func (idx *Index) rebuild() {
+	idx.stagingMu.Lock()
+	subjects := idx.stagedSubjects()
+	idx.timer = nil
+	idx.stagingMu.Unlock()
+
+	idx.Build(subjects)
+}
+
+// stagedSubjects copies the staged subjects, stagingMu must be held by the caller
+func (idx *Index) stagedSubjects() []models.Subject {
+	subjects := make([]models.Subject, 0, len(idx.staging))
+	for _, sub := range idx.staging {
+		subjects = append(subjects, sub)
+	}
+
+	return subjects
+}
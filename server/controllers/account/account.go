@@ -14,7 +14,7 @@ func Profile(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		userID := ctx.MustGet("userID").(string)
 
-		account.Profile(ctx, DB, userID)
+		account.Profile(ctx, DB.WithContext(ctx.Request.Context()), userID)
 	}
 }
 
@@ -29,7 +29,7 @@ func ResetPassword(DB db.Env) func(ctx *gin.Context) {
 			return
 		}
 
-		account.ResetPassword(ctx, DB, &recovery)
+		account.ResetPassword(ctx, DB.WithContext(ctx.Request.Context()), &recovery)
 	}
 }
 
@@ -47,7 +47,7 @@ func ChangePassword(DB db.Env) func(ctx *gin.Context) {
 			return
 		}
 
-		account.ChangePassword(ctx, DB, userID, &reset)
+		account.ChangePassword(ctx, DB.WithContext(ctx.Request.Context()), userID, &reset)
 	}
 }
 
@@ -69,7 +69,7 @@ func Login(DB db.Env) func(ctx *gin.Context) {
 			return
 		}
 
-		account.Login(ctx, DB, &login)
+		account.Login(ctx, DB.WithContext(ctx.Request.Context()), &login)
 	}
 }
 
@@ -83,7 +83,7 @@ func Signup(DB db.Env) func(g *gin.Context) {
 			return
 		}
 
-		account.Signup(ctx, DB, &signupForm)
+		account.Signup(ctx, DB.WithContext(ctx.Request.Context()), &signupForm)
 	}
 }
 
@@ -97,7 +97,7 @@ func VerifyAccount(DB db.Env) func(g *gin.Context) {
 			return
 		}
 
-		account.VerifyAccount(ctx, DB, &verification)
+		account.VerifyAccount(ctx, DB.WithContext(ctx.Request.Context()), &verification)
 	}
 }
 
@@ -113,6 +113,6 @@ func Delete(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		userID := ctx.MustGet("userID").(string)
 
-		account.Delete(ctx, DB, userID)
+		account.Delete(ctx, DB.WithContext(ctx.Request.Context()), userID)
 	}
 }
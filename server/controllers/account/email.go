@@ -18,7 +18,7 @@ func VerifyEmail(DB db.Env) func(g *gin.Context) {
 			return
 		}
 
-		account.VerifyEmail(ctx, DB, &form)
+		account.VerifyEmail(ctx, DB.WithContext(ctx.Request.Context()), &form)
 	}
 }
 
@@ -31,6 +31,6 @@ func RequestPasswordReset(DB db.Env) func(g *gin.Context) {
 			return
 		}
 
-		account.RequestPasswordReset(ctx, DB, &form)
+		account.RequestPasswordReset(ctx, DB.WithContext(ctx.Request.Context()), &form)
 	}
 }
@@ -16,7 +16,7 @@ func GetComment(DB db.Env) func(*gin.Context) {
 		off.Subject = *sub
 
 		userID := ctx.MustGet("userID").(string)
-		private.GetComment(ctx, DB, userID, off)
+		private.GetComment(ctx, DB.WithContext(ctx.Request.Context()), userID, off)
 	}
 }
 
@@ -30,7 +30,7 @@ func GetCommentRating(DB db.Env) func(*gin.Context) {
 		rating.Offering = *off
 
 		userID := ctx.MustGet("userID").(string)
-		private.GetCommentRating(ctx, DB, userID, rating)
+		private.GetCommentRating(ctx, DB.WithContext(ctx.Request.Context()), userID, rating)
 	}
 }
 
@@ -51,7 +51,7 @@ func RateComment(DB db.Env) func(*gin.Context) {
 			return
 		}
 
-		private.RateComment(ctx, DB, userID, rating, &body)
+		private.RateComment(ctx, DB.WithContext(ctx.Request.Context()), userID, rating, &body)
 	}
 }
 
@@ -72,7 +72,7 @@ func ReportComment(DB db.Env) func(*gin.Context) {
 			return
 		}
 
-		private.ReportComment(ctx, DB, userID, report, &body)
+		private.ReportComment(ctx, DB.WithContext(ctx.Request.Context()), userID, report, &body)
 	}
 }
 
@@ -90,6 +90,6 @@ func PublishComment(DB db.Env) func(*gin.Context) {
 			return
 		}
 
-		private.PublishComment(ctx, DB, userID, off, &comment)
+		private.PublishComment(ctx, DB.WithContext(ctx.Request.Context()), userID, off, &comment)
 	}
 }
@@ -0,0 +1,24 @@
+package private
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/server/models/private"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCurriculumPlan is a closure for the GET /private/course/plan endpoint
+func GetCurriculumPlan(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var req controllers.CurriculumPlan
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		userID := ctx.MustGet("userID").(string)
+		private.GetCurriculumPlan(ctx, DB.WithContext(ctx.Request.Context()), userID, &req)
+	}
+}
@@ -0,0 +1,24 @@
+package private
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/server/models/private"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDegreeProgress is a closure for the GET /private/course/progress endpoint
+func GetDegreeProgress(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var course controllers.Course
+		if err := ctx.ShouldBindQuery(&course); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		userID := ctx.MustGet("userID").(string)
+		private.GetDegreeProgress(ctx, DB.WithContext(ctx.Request.Context()), userID, &course)
+	}
+}
@@ -18,7 +18,7 @@ func GetSubjectGrade(DB db.Env) func(ctx *gin.Context) {
 		userID := ctx.MustGet("userID").(string)
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
 
-		private.GetSubjectGrade(ctx, DB, userID, sub)
+		private.GetSubjectGrade(ctx, DB.WithContext(ctx.Request.Context()), userID, sub)
 	}
 }
 
@@ -29,7 +29,7 @@ func GetSubjectReview(DB db.Env) func(ctx *gin.Context) {
 		userID := ctx.MustGet("userID").(string)
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
 
-		private.GetSubjectReview(ctx, DB, userID, sub)
+		private.GetSubjectReview(ctx, DB.WithContext(ctx.Request.Context()), userID, sub)
 	}
 }
 
@@ -48,6 +48,6 @@ func UpdateSubjectReview(DB db.Env) func(ctx *gin.Context) {
 		// get user data
 		userID := ctx.MustGet("userID").(string)
 
-		private.UpdateSubjectReview(ctx, DB, userID, &sr)
+		private.UpdateSubjectReview(ctx, DB.WithContext(ctx.Request.Context()), userID, &sr)
 	}
 }
@@ -0,0 +1,44 @@
+package public
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/server/models/public"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCourseGraph is a closure for the GET /api/course/graph endpoint
+func GetCourseGraph(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var course controllers.Course
+		if err := ctx.ShouldBindQuery(&course); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		var opts controllers.PrerequisiteGraphOptions
+		if err := ctx.ShouldBindQuery(&opts); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		public.GetCourseGraph(ctx, DB.WithContext(ctx.Request.Context()), &course, &opts)
+	}
+}
+
+// GetSubjectGraph is a closure for the GET /api/subject/graph endpoint
+func GetSubjectGraph(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		sub := ctx.MustGet("Subject").(*controllers.Subject)
+
+		var opts controllers.PrerequisiteGraphOptions
+		if err := ctx.ShouldBindQuery(&opts); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		public.GetSubjectGraph(ctx, DB.WithContext(ctx.Request.Context()), sub, &opts)
+	}
+}
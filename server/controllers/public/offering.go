@@ -11,6 +11,6 @@ import (
 func GetOfferings(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
-		public.GetOfferings(ctx, DB, sub)
+		public.GetOfferings(ctx, DB.WithContext(ctx.Request.Context()), sub)
 	}
 }
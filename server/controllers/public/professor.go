@@ -0,0 +1,36 @@
+package public
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/server/models/public"
+	"github.com/gin-gonic/gin"
+)
+
+// GetProfessor is a closure for the GET /api/professor endpoint
+func GetProfessor(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var prof controllers.Professor
+		if err := ctx.ShouldBindQuery(&prof); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		public.GetProfessor(ctx, DB.WithContext(ctx.Request.Context()), &prof)
+	}
+}
+
+// SearchProfessors is a closure for the GET /api/professor/search endpoint
+func SearchProfessors(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var query controllers.ProfessorSearch
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		public.SearchProfessors(ctx, DB.WithContext(ctx.Request.Context()), &query)
+	}
+}
@@ -0,0 +1,23 @@
+package public
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/search"
+	"github.com/Projeto-USPY/uspy-backend/server/models/public"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchSubjects is a closure for the GET /api/subject/search endpoint
+func SearchSubjects(index *search.Index) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var query controllers.SubjectSearch
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		public.SearchSubjects(ctx, index, &query)
+	}
+}
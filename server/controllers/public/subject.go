@@ -10,7 +10,7 @@ import (
 // GetSubject is a closure for the GET /api/subject/all endpoint
 func GetSubjects(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
-		public.GetAllSubjects(ctx, DB)
+		public.GetAllSubjects(ctx, DB.WithContext(ctx.Request.Context()))
 	}
 }
 
@@ -18,7 +18,7 @@ func GetSubjects(DB db.Env) func(ctx *gin.Context) {
 func GetSubjectByCode(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
-		public.Get(ctx, DB, sub)
+		public.Get(ctx, DB.WithContext(ctx.Request.Context()), sub)
 	}
 }
 
@@ -26,6 +26,6 @@ func GetSubjectByCode(DB db.Env) func(ctx *gin.Context) {
 func GetRelations(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
-		public.GetRelations(ctx, DB, sub)
+		public.GetRelations(ctx, DB.WithContext(ctx.Request.Context()), sub)
 	}
 }
@@ -124,6 +124,12 @@ func (s *SubjectSuite) TestGetByCode() {
 				"total": 0,
 				"worth_it": 0
 			},
+			"reviews": {
+				"worth_it": {"count": 0, "average": 0, "histogram": {}},
+				"difficulty": {"count": 0, "average": 0, "histogram": {}},
+				"teaching": {"count": 0, "average": 0, "histogram": {}},
+				"workload": {"count": 0, "average": 0, "histogram": {}}
+			},
 			"requirements": []
 		}
 	`
@@ -13,7 +13,7 @@ func GetOfferingComments(DB db.Env) func(ctx *gin.Context) {
 		off := ctx.MustGet("Offering").(*controllers.Offering)
 		off.Subject = *sub
 
-		restricted.GetOfferingComments(ctx, DB, off)
+		restricted.GetOfferingComments(ctx, DB.WithContext(ctx.Request.Context()), off)
 	}
 }
 
@@ -21,6 +21,6 @@ func GetOfferingComments(DB db.Env) func(ctx *gin.Context) {
 func GetOfferingsWithStats(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
-		restricted.GetOfferingsWithStats(ctx, DB, sub)
+		restricted.GetOfferingsWithStats(ctx, DB.WithContext(ctx.Request.Context()), sub)
 	}
 }
@@ -0,0 +1,36 @@
+package restricted
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/server/models/restricted"
+	"github.com/gin-gonic/gin"
+)
+
+// GetProfessorWithStats is a closure for the GET /api/restricted/professor endpoint
+func GetProfessorWithStats(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var prof controllers.Professor
+		if err := ctx.ShouldBindQuery(&prof); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		restricted.GetProfessorWithStats(ctx, DB.WithContext(ctx.Request.Context()), &prof)
+	}
+}
+
+// GetProfessorComments is a closure for the GET /api/restricted/professor/comments endpoint
+func GetProfessorComments(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		var query controllers.ProfessorComments
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		restricted.GetProfessorComments(ctx, DB.WithContext(ctx.Request.Context()), &query)
+	}
+}
@@ -12,6 +12,30 @@ import (
 func GetGrades(DB db.Env) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
 		sub := ctx.MustGet("Subject").(*controllers.Subject)
-		restricted.GetGrades(ctx, DB, sub)
+		restricted.GetGrades(ctx, DB.WithContext(ctx.Request.Context()), sub)
+	}
+}
+
+// GetGradesByProfessor is a closure for the GET /api/restricted/subject/grades/professors endpoint
+func GetGradesByProfessor(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		sub := ctx.MustGet("Subject").(*controllers.Subject)
+		restricted.GetGradesByProfessor(ctx, DB.WithContext(ctx.Request.Context()), sub)
+	}
+}
+
+// GetGradesByYear is a closure for the GET /api/restricted/subject/grades/years endpoint
+func GetGradesByYear(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		sub := ctx.MustGet("Subject").(*controllers.Subject)
+		restricted.GetGradesByYear(ctx, DB.WithContext(ctx.Request.Context()), sub)
+	}
+}
+
+// GetGradeTrend is a closure for the GET /api/restricted/subject/grades/trend endpoint
+func GetGradeTrend(DB db.Env) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		sub := ctx.MustGet("Subject").(*controllers.Subject)
+		restricted.GetGradeTrend(ctx, DB.WithContext(ctx.Request.Context()), sub)
 	}
 }
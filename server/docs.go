@@ -0,0 +1,98 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/Projeto-USPY/uspy-backend/server/openapi"
+)
+
+// routeDocs documents every route registered by setupRoutes, TestRoutesDocumented fails if one is missing
+//
+// Routes are documented once, documentedRoutes adds their /v2 versions
+var routeDocs = []openapi.Route{
+	// account
+	{Method: http.MethodDelete, Path: "/account", Tag: "account", Auth: true, Summary: "Delete the logged in user's account"},
+	{Method: http.MethodGet, Path: "/account/captcha", Tag: "account", Summary: "Get the captcha image needed to sign up", Response: []byte{}, ContentType: "image/*"},
+	{Method: http.MethodGet, Path: "/account/logout", Tag: "account", Auth: true, Summary: "Remove the access token cookie"},
+	{Method: http.MethodGet, Path: "/account/profile", Tag: "account", Auth: true, Summary: "Get the logged in user's profile", Response: views.Profile{}},
+	{Method: http.MethodPost, Path: "/account/login", Tag: "account", Summary: "Log in and set the access token cookie", Body: controllers.Login{}, Response: views.Profile{}},
+	{Method: http.MethodPost, Path: "/account/create", Tag: "account", Summary: "Sign up with a uspdigital transcript", Body: controllers.SignupForm{}, Response: views.Transcript{}},
+	{Method: http.MethodPut, Path: "/account/password_change", Tag: "account", Auth: true, Summary: "Change the logged in user's password", Body: controllers.PasswordChange{}},
+	{Method: http.MethodPut, Path: "/account/password_reset", Tag: "account", Summary: "Reset a password with the token sent by email", Body: controllers.PasswordRecovery{}},
+	{Method: http.MethodGet, Path: "/account/verify", Tag: "account", Summary: "Verify an account with the token sent by email", Query: []interface{}{controllers.AccountVerification{}}},
+	{Method: http.MethodPost, Path: "/account/email/verification", Tag: "account", Summary: "Send the account verification email again", Body: controllers.EmailVerificationSubmission{}},
+	{Method: http.MethodPost, Path: "/account/email/password_reset", Tag: "account", Summary: "Send a password reset email", Body: controllers.EmailVerificationSubmission{}},
+
+	// public
+	{Method: http.MethodGet, Path: "/api/subject/all", Tag: "public", Summary: "List every course and its subjects", Response: []views.Course{}},
+	{Method: http.MethodGet, Path: "/api/subject/search", Tag: "public", Summary: "Search subjects by code, name and description", Query: []interface{}{controllers.SubjectSearch{}}, Response: []views.SubjectSearchResult{}},
+	{Method: http.MethodGet, Path: "/api/course/graph", Tag: "public", Summary: "Get the prerequisite graph of a course", Query: []interface{}{controllers.Course{}, controllers.PrerequisiteGraphOptions{}}, Response: views.PrerequisiteGraph{}},
+	{Method: http.MethodGet, Path: "/api/professor", Tag: "public", Summary: "Get a professor and the subjects they teach", Query: []interface{}{controllers.Professor{}}, Response: views.Professor{}},
+	{Method: http.MethodGet, Path: "/api/professor/search", Tag: "public", Summary: "Search professors by name", Query: []interface{}{controllers.ProfessorSearch{}}, Response: []views.ProfessorSearchResult{}},
+	{Method: http.MethodGet, Path: "/api/subject", Tag: "public", Summary: "Get a subject", Query: []interface{}{controllers.Subject{}}, Response: views.Subject{}},
+	{Method: http.MethodGet, Path: "/api/subject/relations", Tag: "public", Summary: "Get a subject's direct predecessors and successors", Query: []interface{}{controllers.Subject{}}, Response: views.SubjectGraph{}},
+	{Method: http.MethodGet, Path: "/api/subject/graph", Tag: "public", Summary: "Get the prerequisite graph around a subject", Query: []interface{}{controllers.Subject{}, controllers.PrerequisiteGraphOptions{}}, Response: views.PrerequisiteGraph{}},
+	{Method: http.MethodGet, Path: "/api/subject/offerings", Tag: "public", Summary: "List some of a subject's offerings", Query: []interface{}{controllers.Subject{}}, Response: []views.Offering{}},
+
+	// restricted
+	{Method: http.MethodGet, Path: "/api/restricted/professor", Tag: "restricted", Auth: true, Summary: "Get a professor with the stats of their offerings", Query: []interface{}{controllers.Professor{}}, Response: views.Professor{}},
+	{Method: http.MethodGet, Path: "/api/restricted/professor/comments", Tag: "restricted", Auth: true, Summary: "List the comments on a professor's offerings, most recent first, paged by cursor", Query: []interface{}{controllers.ProfessorComments{}}, Response: views.ProfessorComments{}},
+	{Method: http.MethodGet, Path: "/api/restricted/subject/grades", Tag: "restricted", Auth: true, Summary: "Get a subject's grade distribution", Query: []interface{}{controllers.Subject{}}, Response: views.GradeDistribution{}},
+	{Method: http.MethodGet, Path: "/api/restricted/subject/grades/professors", Tag: "restricted", Auth: true, Summary: "Get a subject's grade distribution by professor", Query: []interface{}{controllers.Subject{}}, Response: []views.ProfessorGrades{}},
+	{Method: http.MethodGet, Path: "/api/restricted/subject/grades/years", Tag: "restricted", Auth: true, Summary: "Get a subject's grade distribution by year", Query: []interface{}{controllers.Subject{}}, Response: []views.YearGrades{}},
+	{Method: http.MethodGet, Path: "/api/restricted/subject/grades/trend", Tag: "restricted", Auth: true, Summary: "Get a subject's average grade and approval rate by semester", Query: []interface{}{controllers.Subject{}}, Response: []views.PeriodGrades{}},
+	{Method: http.MethodGet, Path: "/api/restricted/subject/offerings", Tag: "restricted", Auth: true, Summary: "List a subject's offerings with their approval stats", Query: []interface{}{controllers.Subject{}}, Response: []views.Offering{}},
+	{Method: http.MethodGet, Path: "/api/restricted/subject/offerings/comments", Tag: "restricted", Auth: true, Summary: "List the comments on an offering", Query: []interface{}{controllers.Offering{}}, Response: []views.Comment{}},
+
+	// private
+	{Method: http.MethodGet, Path: "/private/course/plan", Tag: "private", Auth: true, Summary: "Plan the semesters left in the user's course", Query: []interface{}{controllers.CurriculumPlan{}}, Response: views.CurriculumPlan{}},
+	{Method: http.MethodGet, Path: "/private/course/progress", Tag: "private", Auth: true, Summary: "Get the user's progress in a course", Query: []interface{}{controllers.Course{}}, Response: views.DegreeProgress{}},
+	{Method: http.MethodGet, Path: "/private/subject/grade", Tag: "private", Auth: true, Summary: "Get the user's grade in a subject", Query: []interface{}{controllers.Subject{}}, Response: views.Record{}},
+	{Method: http.MethodGet, Path: "/private/subject/review", Tag: "private", Auth: true, Summary: "Get the user's review of a subject", Query: []interface{}{controllers.Subject{}}, Response: views.SubjectReview{}},
+	{Method: http.MethodPost, Path: "/private/subject/review", Tag: "private", Auth: true, Summary: "Review a subject", Query: []interface{}{controllers.Subject{}}, Body: controllers.SubjectReview{}},
+	{Method: http.MethodGet, Path: "/private/subject/offerings/comments", Tag: "private", Auth: true, Summary: "Get the user's comment on an offering", Query: []interface{}{controllers.Offering{}}, Response: views.Comment{}},
+	{Method: http.MethodPut, Path: "/private/subject/offerings/comments", Tag: "private", Auth: true, Summary: "Publish or edit the user's comment on an offering", Query: []interface{}{controllers.Offering{}}, Body: controllers.Comment{}, Response: views.Comment{}},
+	{Method: http.MethodGet, Path: "/private/subject/offerings/comments/rating", Tag: "private", Auth: true, Summary: "Get the user's rating of a comment", Query: []interface{}{controllers.CommentRating{}}, Response: views.CommentRating{}},
+	{Method: http.MethodPut, Path: "/private/subject/offerings/comments/rating", Tag: "private", Auth: true, Summary: "Upvote or downvote a comment", Query: []interface{}{controllers.CommentRating{}}, Body: controllers.CommentRateBody{}},
+	{Method: http.MethodPut, Path: "/private/subject/offerings/comments/report", Tag: "private", Auth: true, Summary: "Report a comment", Query: []interface{}{controllers.CommentRating{}}, Body: controllers.CommentReportBody{}},
+}
+
+// documentedRoutes lists routeDocs once as is and once under /v2, where errors are envelopes
+func documentedRoutes() []openapi.Route {
+	routes := make([]openapi.Route, 0, 2*len(routeDocs))
+	routes = append(routes, routeDocs...)
+	for _, r := range routeDocs {
+		r.Path = middleware.V2Prefix + r.Path
+		r.Error = views.ErrorEnvelope{}
+		routes = append(routes, r)
+	}
+
+	return routes
+}
+
+// apiDocument describes the API in OpenAPI 3
+func apiDocument() *openapi.Document {
+	return openapi.Build(openapi.Info{
+		Title:       "USPY API",
+		Description: "Subjects, professors, grades and reviews of USP courses. Routes under /v2 always describe their errors with an envelope",
+		Version:     "2.0",
+	}, documentedRoutes())
+}
+
+// swaggerUI is served at /docs, it loads Swagger UI from a CDN and points it at /openapi.json
+const swaggerUI = `<!DOCTYPE html>
+<html>
+<head>
+	<title>USPY API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@4/swagger-ui-bundle.js"></script>
+	<script>SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"})</script>
+</body>
+</html>
+`
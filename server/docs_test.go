@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/search"
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	setupRoutes(db.Env{}, search.NewIndex(), &r.RouterGroup)
+	setupRoutes(db.Env{}, search.NewIndex(), r.Group(middleware.V2Prefix))
+
+	documented := make(map[string]bool)
+	for _, doc := range documentedRoutes() {
+		assert.False(t, documented[doc.Key()], "%s is documented twice", doc.Key())
+		documented[doc.Key()] = true
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		assert.True(t, documented[key], "%s is not documented in routeDocs", key)
+	}
+
+	for key := range documented {
+		assert.True(t, registered[key], "%s is documented but not registered", key)
+	}
+}
+
+func TestAPIDocument(t *testing.T) {
+	doc := apiDocument()
+
+	_, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	subject := doc.Paths["/api/subject"]["get"]
+	require.NotNil(t, subject)
+	require.Len(t, subject.Parameters, 4)
+	assert.Equal(t, "code", subject.Parameters[0].Name)
+	assert.True(t, subject.Parameters[0].Required)
+	assert.Equal(t, "#/components/schemas/views.Subject", subject.Responses["200"].Content["application/json"].Schema.Ref)
+
+	// every referenced schema is defined
+	for name, schema := range doc.Components.Schemas {
+		assert.NotNil(t, schema, name)
+	}
+
+	login := doc.Paths["/account/login"]["post"]
+	require.NotNil(t, login)
+	assert.Equal(t, "#/components/schemas/controllers.Login", login.RequestBody.Content["application/json"].Schema.Ref)
+	assert.ElementsMatch(t, []string{"login", "pwd"}, doc.Components.Schemas["controllers.Login"].Required)
+
+	v2 := doc.Paths["/v2/api/subject"]["get"]
+	require.NotNil(t, v2)
+	assert.Equal(t, "#/components/schemas/views.ErrorEnvelope", v2.Responses["400"].Content["application/json"].Schema.Ref)
+	assert.Nil(t, subject.Responses["400"].Content, "errors of the original routes have no documented body")
+
+	rating := doc.Paths["/private/subject/offerings/comments/rating"]["put"]
+	require.NotNil(t, rating)
+	assert.NotEmpty(t, rating.Security, "private routes require the access token")
+	assert.Len(t, rating.Parameters, 6, "nested binders share the subject and offering parameters")
+}
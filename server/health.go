@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/api/iterator"
+)
+
+// readinessTimeout bounds all readiness checks, probes must be answered quickly
+const readinessTimeout = 3 * time.Second
+
+// draining is set once shutdown starts, so load balancers stop routing requests to this instance
+var draining int32
+
+// check returns an error if a dependency of the server is not usable
+type check func(ctx context.Context) error
+
+// readinessChecks lists what /readyz verifies before the instance receives traffic
+func readinessChecks(DB db.Env) map[string]check {
+	binary := func(name string) check {
+		return func(context.Context) error {
+			_, err := exec.LookPath(name)
+			return err
+		}
+	}
+
+	return map[string]check{
+		"firestore": func(ctx context.Context) error {
+			_, err := DB.Client.Collection("courses").Limit(1).Documents(ctx).Next()
+			if errors.Is(err, iterator.Done) {
+				return nil
+			}
+			return err
+		},
+		"pdftotext": binary("pdftotext"), // signup converts transcripts with poppler-utils
+		"pdfinfo":   binary("pdfinfo"),
+		"mail": func(context.Context) error {
+			// emails are only sent in the cloud, see config.Setup
+			if config.Env.IsUsingProjectID() && !config.Env.Mailjet.Configured() {
+				return errors.New("mailjet credentials are missing")
+			}
+			return nil
+		},
+	}
+}
+
+// Healthz is the liveness probe, it only tells that the server is responding
+func Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, views.Health{Status: "ok"})
+}
+
+// Readyz is the readiness probe, it runs the checks concurrently and fails if any of them fails or the server is draining
+//
+// Errors are logged rather than returned, since the probe is public
+func Readyz(checks map[string]check) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if atomic.LoadInt32(&draining) == 1 {
+			ctx.JSON(http.StatusServiceUnavailable, views.Health{Status: "draining"})
+			return
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), readinessTimeout)
+		defer cancel()
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		health := views.Health{Status: "ok", Checks: make(map[string]string, len(checks))}
+		for name, c := range checks {
+			wg.Add(1)
+			go func(name string, c check) {
+				defer wg.Done()
+				err := c(checkCtx)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					logger.FromContext(ctx).Warn("readiness check failed", logger.Fields{"check": name, "error": logger.Redact(err.Error())})
+					health.Checks[name] = "failed"
+					health.Status = "unavailable"
+				} else {
+					health.Checks[name] = "ok"
+				}
+			}(name, c)
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		if health.Status != "ok" {
+			status = http.StatusServiceUnavailable
+		}
+
+		ctx.JSON(status, health)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error {
+		return errors.New("projects/p/databases/(default)/documents/courses: unavailable")
+	}
+
+	r := gin.New()
+	r.GET("/healthz", Healthz)
+	r.GET("/ready", Readyz(map[string]check{"firestore": ok, "pdfinfo": ok}))
+	r.GET("/unready", Readyz(map[string]check{"firestore": failing, "pdfinfo": ok}))
+
+	w := utils.MakeRequest(r, http.MethodGet, "/healthz", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = utils.MakeRequest(r, http.MethodGet, "/ready", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok", "checks": {"firestore": "ok", "pdfinfo": "ok"}}`, w.Body.String())
+
+	w = utils.MakeRequest(r, http.MethodGet, "/unready", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status": "unavailable", "checks": {"firestore": "failed", "pdfinfo": "ok"}}`, w.Body.String(), "errors are not exposed")
+
+	atomic.StoreInt32(&draining, 1)
+	defer atomic.StoreInt32(&draining, 0)
+	w = utils.MakeRequest(r, http.MethodGet, "/ready", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "draining")
+}
+
+func TestRunDrains(t *testing.T) {
+	previous := config.Env
+	defer func() { config.Env = previous }()
+	config.Env.HTTPServer = config.HTTPServer{ReadTimeout: time.Second, WriteTimeout: time.Second, ShutdownTimeout: time.Second}
+
+	// find a free port
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	goWorker(func() { <-workerCtx.Done() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- Run(ctx, addr, handler) }()
+
+	var resp *http.Response
+	var reqErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ { // retried until the server listens
+			if resp, reqErr = http.Get("http://" + addr); reqErr == nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	<-started
+	cancel()
+	stopWorker()
+
+	require.NoError(t, <-runErr)
+	<-done
+	require.NoError(t, reqErr)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode, "in-flight requests finish")
+	_ = resp.Body.Close()
+}
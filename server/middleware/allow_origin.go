@@ -12,7 +12,7 @@ import (
 func AllowAnyOrigin() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctx.Header("Access-Control-Allow-Credentials", "true")
-		ctx.Header("Access-Control-Allow-Origin", "http://127.0.0.1")
+		ctx.Header("Access-Control-Allow-Origin", allowedOrigin(ctx))
 		ctx.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
 		ctx.Header("Access-Control-Allow-Headers", "Content-Type, Access-Control-Allow-Headers, Authorization, X-Requested-With,observe")
 
@@ -26,21 +26,14 @@ func AllowAnyOrigin() gin.HandlerFunc {
 
 }
 
-// AllowUSPYOrigin enables CORS for the Frontend, according to dev/prod environment variables
+// AllowUSPYOrigin enables CORS for the Frontend, see config.Frontend
 func AllowUSPYOrigin() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctx.Header("Access-Control-Allow-Credentials", "true")
 		ctx.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
 		ctx.Header("Access-Control-Allow-Headers", "Content-Type, Access-Control-Allow-Headers, Authorization, X-Requested-With,observe")
 
-		var frontURL string
-		if config.Env.Mode == "dev" {
-			frontURL = "https://frontdev.uspy.me"
-		} else {
-			frontURL = "https://uspy.me"
-		}
-
-		ctx.Header("Access-Control-Allow-Origin", frontURL)
+		ctx.Header("Access-Control-Allow-Origin", allowedOrigin(ctx))
 		ctx.SetSameSite(http.SameSiteNoneMode)
 
 		if ctx.Request.Method == "OPTIONS" {
@@ -49,3 +42,18 @@ func AllowUSPYOrigin() gin.HandlerFunc {
 		}
 	}
 }
+
+// allowedOrigin returns the origin of the request if it is allowed, or else the front-end's
+func allowedOrigin(ctx *gin.Context) string {
+	// the response depends on the origin, caches must not share it between origins
+	ctx.Writer.Header().Add("Vary", "Origin")
+
+	origin := ctx.GetHeader("Origin")
+	for _, allowed := range config.Env.AllowedOrigins {
+		if origin == allowed {
+			return origin
+		}
+	}
+
+	return config.Env.FrontendURL
+}
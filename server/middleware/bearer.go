@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BearerToken is a middleware that requires the Authorization header to carry token, it allows every request if token is empty
+func BearerToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(ctx *gin.Context) {
+		if token == "" {
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), expected) != 1 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache-Control policies, see CacheControl
+const (
+	// PublicCache is for catalog data, which is the same for every user and changes rarely
+	PublicCache = "public, max-age=300, must-revalidate"
+
+	// PrivateCache is for data of the logged in user, browsers may keep it but must revalidate it with its ETag
+	PrivateCache = "private, no-cache"
+
+	// NoStore is for responses that must never be kept, like account operations
+	NoStore = "no-store"
+)
+
+// cacheControlWriter sets the Cache-Control header once the status is known, so errors are never cached
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	policy string
+	set    bool // whether the header was set by this writer rather than the handler
+}
+
+func (w *cacheControlWriter) apply(code int) {
+	if w.Written() || (!w.set && w.Header().Get("Cache-Control") != "") {
+		return
+	}
+
+	if code >= http.StatusBadRequest {
+		w.Header().Set("Cache-Control", NoStore)
+	} else {
+		w.Header().Set("Cache-Control", w.policy)
+	}
+
+	w.set = true
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	w.apply(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.apply(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+// CacheControl is a middleware that applies a Cache-Control policy to the responses of a route group
+//
+// Error responses are never cached, and handlers can still set their own Cache-Control header
+func CacheControl(policy string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Writer = &cacheControlWriter{ResponseWriter: ctx.Writer, policy: policy}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.ETag())
+	api := router.Group("/api", middleware.CacheControl(middleware.PublicCache))
+	api.GET("/subject/all", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, []string{"SCC0222"})
+	})
+	api.GET("/subject", func(ctx *gin.Context) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	})
+	api.GET("/custom", func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", middleware.NoStore)
+		ctx.String(http.StatusOK, "custom")
+	})
+
+	w := utils.MakeRequest(router, http.MethodGet, "/api/subject/all", nil)
+	assert.Equal(t, middleware.PublicCache, w.Header().Get("Cache-Control"))
+
+	w = conditionalRequest(router, "/api/subject/all", w.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, middleware.PublicCache, w.Header().Get("Cache-Control"), "revalidated responses keep the policy")
+
+	w = utils.MakeRequest(router, http.MethodGet, "/api/subject", nil)
+	assert.Equal(t, middleware.NoStore, w.Header().Get("Cache-Control"), "errors are never cached")
+
+	w = utils.MakeRequest(router, http.MethodGet, "/api/custom", nil)
+	assert.Equal(t, middleware.NoStore, w.Header().Get("Cache-Control"), "handlers can set their own policy")
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// CompressMinSize is the smallest response body that is compressed, smaller ones would barely shrink
+const CompressMinSize = 1024
+
+// encoders are the supported content codings, in order of preference
+var encoders = []struct {
+	name string
+	new  func(w io.Writer) io.WriteCloser
+}{
+	{"br", func(w io.Writer) io.WriteCloser { return brotli.NewWriterLevel(w, brotli.DefaultCompression) }},
+	{"gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+}
+
+// negotiateEncoding picks the preferred encoding accepted by an Accept-Encoding header, or "" for none
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				if parsed, err := strconv.ParseFloat(value[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		accepted[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, e := range encoders {
+		q, ok := accepted[e.name]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = e.name, q
+		}
+	}
+
+	return best
+}
+
+// compressible reports whether a content type is worth compressing, images and archives are already compressed
+func compressible(contentType string) bool {
+	contentType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.HasSuffix(contentType, "json") ||
+		strings.HasSuffix(contentType, "xml") ||
+		contentType == "application/javascript"
+}
+
+// compressWriter compresses the response body, deciding on the first write whether it is worth it
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	encoder  io.WriteCloser
+	decided  bool
+}
+
+func (w *compressWriter) decide(size int) {
+	w.decided = true
+
+	status := w.Status()
+	header := w.Header()
+	if status == http.StatusNoContent || status == http.StatusNotModified || header.Get("Content-Encoding") != "" ||
+		size < CompressMinSize || !compressible(header.Get("Content-Type")) {
+		return
+	}
+
+	for _, e := range encoders {
+		if e.name == w.encoding {
+			w.encoder = e.new(w.ResponseWriter)
+		}
+	}
+
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+
+	// each encoding is a different representation, so it needs its own ETag
+	if etag := header.Get("ETag"); strings.HasSuffix(etag, `"`) {
+		header.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+w.encoding+`"`)
+	}
+}
+
+// WriteHeaderNow tags the ETag of 304 responses as decide would, since there is no body to decide on
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided && w.Status() == http.StatusNotModified {
+		w.decided = true
+		if etag := w.Header().Get("ETag"); strings.HasSuffix(etag, `"`) {
+			w.Header().Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+w.encoding+`"`)
+		}
+	}
+
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide(len(data))
+	}
+
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(data)
+	}
+
+	return w.encoder.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// stripEncodingSuffixes removes the suffixes added to ETags by compressWriter, so handlers compare If-None-Match with their own ETags
+func stripEncodingSuffixes(ifNoneMatch string) string {
+	for _, e := range encoders {
+		ifNoneMatch = strings.ReplaceAll(ifNoneMatch, "-"+e.name+`"`, `"`)
+	}
+
+	return ifNoneMatch
+}
+
+// Compress is a middleware that compresses responses with brotli or gzip, according to the request's Accept-Encoding
+//
+// Only text and JSON bodies of at least CompressMinSize bytes are compressed
+func Compress() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Vary", "Accept-Encoding")
+		if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" {
+			ctx.Request.Header.Set("If-None-Match", stripEncodingSuffixes(ifNoneMatch))
+		}
+
+		encoding := negotiateEncoding(ctx.GetHeader("Accept-Encoding"))
+		if encoding == "" || ctx.Request.Method == http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: ctx.Writer, encoding: encoding}
+		ctx.Writer = w
+		ctx.Next()
+
+		if w.encoder != nil {
+			_ = w.encoder.Close()
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressedRequest(router *gin.Engine, endpoint, acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, endpoint, nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat("Introdução à Ciência de Computação ", 100)
+	router := gin.New()
+	router.Use(middleware.Compress(), middleware.ETag())
+	router.GET("/large", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, large)
+	})
+	router.GET("/small", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "SCC0222")
+	})
+
+	tests := []struct {
+		acceptEncoding string
+		want           string
+		decode         func(r io.Reader) (io.Reader, error)
+	}{
+		{"gzip, deflate", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"gzip;q=0.5, br", "br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+		{"br;q=0, gzip", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"identity", "", func(r io.Reader) (io.Reader, error) { return r, nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			w := compressedRequest(router, "/large", tt.acceptEncoding, "")
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+			r, err := tt.decode(w.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, large, string(body))
+
+			// the ETag of a compressed response is still valid for revalidation
+			w = compressedRequest(router, "/large", tt.acceptEncoding, w.Header().Get("ETag"))
+			assert.Equal(t, http.StatusNotModified, w.Code)
+		})
+	}
+
+	w := compressedRequest(router, "/small", "gzip", "")
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "small responses are not compressed")
+	assert.Equal(t, "SCC0222", w.Body.String())
+}
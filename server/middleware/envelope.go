@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/Projeto-USPY/uspy-backend/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// V2Prefix is the root of the API version whose errors are always written as a views.ErrorEnvelope
+const V2Prefix = "/v2"
+
+// errorCodes are the codes of errors whose handlers did not write one
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusMethodNotAllowed:    "method_not_allowed",
+	http.StatusConflict:            "conflict",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusInternalServerError: "internal_error",
+	http.StatusServiceUnavailable:  "unavailable",
+	http.StatusGatewayTimeout:      "timeout",
+}
+
+// envelopeWriter holds back error responses, so they can be rewritten as envelopes once the handlers return
+type envelopeWriter struct {
+	gin.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	written bool
+}
+
+func (w *envelopeWriter) failed() bool {
+	return w.status >= http.StatusBadRequest
+}
+
+func (w *envelopeWriter) WriteHeader(code int) {
+	if code <= 0 || w.written {
+		return
+	}
+
+	w.status = code
+	if !w.failed() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *envelopeWriter) WriteHeaderNow() {
+	w.written = true
+	if !w.failed() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	w.written = true
+	if w.failed() {
+		return w.body.Write(data)
+	}
+
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *envelopeWriter) Status() int {
+	return w.status
+}
+
+func (w *envelopeWriter) Written() bool {
+	return w.written
+}
+
+// fieldErrors describes the validation errors attached to the context, in the request's language
+func fieldErrors(ctx *gin.Context) []views.FieldError {
+	lang := i18n.FromContext(ctx)
+	fields := make([]views.FieldError, 0)
+	for _, e := range ctx.Errors {
+		var validationErrs validator.ValidationErrors
+		var typeErr *json.UnmarshalTypeError
+
+		switch {
+		case errors.As(e.Err, &validationErrs):
+			for _, fe := range validationErrs {
+				kind := fe.Kind()
+				length := kind == reflect.String || kind == reflect.Slice || kind == reflect.Map || kind == reflect.Array
+				fields = append(fields, views.FieldError{
+					Field:   fe.Field(),
+					Rule:    fe.Tag(),
+					Message: i18n.FieldMessage(lang, fe.Tag(), fe.Param(), length),
+				})
+			}
+		case errors.As(e.Err, &typeErr):
+			fields = append(fields, views.FieldError{
+				Field:   typeErr.Field,
+				Rule:    "type",
+				Message: i18n.FieldMessage(lang, "type", "", false),
+			})
+		}
+	}
+
+	return fields
+}
+
+// newErrorEnvelope builds the envelope of an error response
+//
+// Errors written by handlers as views.Error keep their code, messages are translated to the request's language
+// and server error details are never exposed
+func newErrorEnvelope(ctx *gin.Context, status int, body []byte) views.ErrorEnvelope {
+	var handlerErr views.Error
+	_ = json.Unmarshal(body, &handlerErr)
+
+	detail := views.ErrorDetail{Code: handlerErr.Code, Message: handlerErr.Message, RequestID: ctx.GetString("requestID")}
+	if status >= http.StatusInternalServerError && errors.Is(ctx.Request.Context().Err(), context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+
+	if detail.Code == "" {
+		detail.Code = errorCodes[status]
+		if detail.Code == "" {
+			detail.Code = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+		}
+	}
+
+	if status == http.StatusBadRequest {
+		if fields := fieldErrors(ctx); len(fields) > 0 {
+			detail.Code = "validation_failed"
+			detail.Fields = fields
+		}
+	}
+
+	if detail.Message == "" {
+		detail.Message = http.StatusText(status)
+	}
+	detail.Message = i18n.Message(i18n.FromContext(ctx), detail.Code, detail.Message)
+
+	return views.ErrorEnvelope{Error: detail}
+}
+
+// ErrorEnvelope is a middleware that rewrites every error response under V2Prefix as a views.ErrorEnvelope
+//
+// It is registered globally rather than on the /v2 group, so errors of the rate limiter and unknown routes are covered too
+func ErrorEnvelope() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		path := ctx.Request.URL.Path
+		if path != V2Prefix && !strings.HasPrefix(path, V2Prefix+"/") {
+			ctx.Next()
+			return
+		}
+
+		original := ctx.Writer
+		w := &envelopeWriter{ResponseWriter: original, status: original.Status()}
+		ctx.Writer = w
+		ctx.Next()
+		ctx.Writer = original
+
+		if !w.failed() {
+			return
+		}
+
+		envelope, err := json.Marshal(newErrorEnvelope(ctx, w.status, w.body.Bytes()))
+		if err != nil {
+			envelope = []byte(`{"error":{"code":"internal_error","message":"Internal Server Error"}}`)
+		}
+
+		original.Header().Set("Content-Type", "application/json; charset=utf-8")
+		original.Header().Del("Content-Length")
+		original.WriteHeader(w.status)
+		_, _ = original.Write(envelope)
+	}
+}
@@ -0,0 +1,150 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/validation"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signup struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,validatePassword"`
+}
+
+func decodeEnvelope(t *testing.T, w *httptest.ResponseRecorder) views.ErrorDetail {
+	var envelope views.ErrorEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope), w.Body.String())
+	return envelope.Error
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.SetupValidators())
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Locale(), middleware.ErrorEnvelope())
+	for _, root := range []*gin.RouterGroup{&router.RouterGroup, router.Group(middleware.V2Prefix)} {
+		root.POST("/signup", func(ctx *gin.Context) {
+			var form signup
+			if err := ctx.ShouldBindJSON(&form); err != nil {
+				ctx.AbortWithError(http.StatusBadRequest, err)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, form)
+		})
+		root.GET("/login", func(ctx *gin.Context) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, views.ErrInvalidCredentials)
+		})
+		root.GET("/profile", func(ctx *gin.Context) {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+		})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		body := `{"email": "not an email", "password": "short"}`
+		w := utils.MakeRequest(router, http.MethodPost, "/v2/signup", strings.NewReader(body))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+		detail := decodeEnvelope(t, w)
+		assert.Equal(t, "validation_failed", detail.Code)
+		assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), detail.RequestID)
+		require.Len(t, detail.Fields, 2)
+		assert.Equal(t, "Alguns campos são inválidos.", detail.Message)
+		assert.Equal(t, views.FieldError{Field: "email", Rule: "email", Message: "deve ser um endereço de e-mail"}, detail.Fields[0])
+		assert.Equal(t, views.FieldError{
+			Field:   "password",
+			Rule:    "validatePassword",
+			Message: "deve ter de 8 a 20 caracteres, com letras, números e símbolos",
+		}, detail.Fields[1])
+	})
+
+	t.Run("localized", func(t *testing.T) {
+		body := `{"email": "user@usp.br", "password": "short"}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/signup", strings.NewReader(body))
+		req.Header.Set("Accept-Language", "en-US,en;q=0.9,pt;q=0.8")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		detail := decodeEnvelope(t, w)
+		assert.Equal(t, "Some fields are invalid.", detail.Message)
+		require.Len(t, detail.Fields, 1)
+		assert.Equal(t, "must have 8 to 20 characters, with letters, digits and symbols", detail.Fields[0].Message)
+
+		req = httptest.NewRequest(http.MethodGet, "/v2/login", nil)
+		req.Header.Set("Accept-Language", "pt-BR")
+		req.AddCookie(&http.Cookie{Name: middleware.LanguageCookie, Value: "en"})
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, "Wrong login or password.", decodeEnvelope(t, w).Message, "the cookie takes precedence")
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		w := utils.MakeRequest(router, http.MethodGet, "/v2/login", nil)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		detail := decodeEnvelope(t, w)
+		assert.Equal(t, views.ErrInvalidCredentials.Code, detail.Code)
+		assert.Equal(t, views.ErrInvalidCredentials.Message, detail.Message)
+	})
+
+	t.Run("status only", func(t *testing.T) {
+		w := utils.MakeRequest(router, http.MethodGet, "/v2/profile", nil)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "unauthorized", decodeEnvelope(t, w).Code)
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		w := utils.MakeRequest(router, http.MethodGet, "/v2/nothing", nil)
+		require.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "not_found", decodeEnvelope(t, w).Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		body := `{"email": "user@usp.br", "password": "S3nha-segura"}`
+		w := utils.MakeRequest(router, http.MethodPost, "/v2/signup", strings.NewReader(body))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, body, w.Body.String())
+	})
+
+	t.Run("original routes", func(t *testing.T) {
+		w := utils.MakeRequest(router, http.MethodGet, "/profile", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, w.Body.String(), "errors outside /v2 keep their body")
+
+		w = utils.MakeRequest(router, http.MethodGet, "/login", nil)
+		assert.JSONEq(t, `{"code": "invalid_credentials", "message": "Login ou senha incorretos."}`, w.Body.String())
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.GET("/", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.GetString("requestID"))
+	})
+
+	w := utils.MakeRequest(router, http.MethodGet, "/", nil)
+	assert.NotEmpty(t, w.Body.String())
+	assert.Equal(t, w.Body.String(), w.Header().Get(middleware.RequestIDHeader))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "frontend-1234")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "frontend-1234", w.Body.String(), "valid client IDs are kept")
+}
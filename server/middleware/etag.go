@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter holds the response until the handler returns, so its ETag can be computed before anything is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	status  int
+	written bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// matchesETag reports whether an If-None-Match header lists the given ETag
+//
+// Weak comparison is used, as RFC 7232 requires for If-None-Match
+func matchesETag(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ETag is a middleware that sets a strong ETag on successful GET responses and answers If-None-Match with 304 Not Modified
+//
+// Responses that already have an ETag, like the cached catalog, are left as they are
+func ETag() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		original := ctx.Writer
+		w := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		ctx.Writer = w
+		ctx.Next()
+		ctx.Writer = original
+
+		if w.status == http.StatusOK && original.Header().Get("ETag") == "" {
+			sum := sha256.Sum256(w.body.Bytes())
+			original.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		}
+
+		if w.status == http.StatusOK && matchesETag(ctx.GetHeader("If-None-Match"), original.Header().Get("ETag")) {
+			original.Header().Del("Content-Type")
+			original.Header().Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+
+		original.WriteHeader(w.status)
+		if w.body.Len() > 0 {
+			_, _ = original.Write(w.body.Bytes())
+		} else {
+			original.WriteHeaderNow()
+		}
+	}
+}
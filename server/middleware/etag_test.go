@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conditionalRequest(router *gin.Engine, endpoint, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, endpoint, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.ETag())
+	router.GET("/courses", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, []string{"55041", "55090"})
+	})
+	router.GET("/cached", func(ctx *gin.Context) {
+		ctx.Header("ETag", `"handler"`)
+		ctx.JSON(http.StatusOK, []string{"55041"})
+	})
+	router.GET("/error", func(ctx *gin.Context) {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+
+	w := conditionalRequest(router, "/courses", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, `["55041","55090"]`, w.Body.String())
+
+	w = conditionalRequest(router, "/courses", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	w = conditionalRequest(router, "/courses", `"stale", W/`+etag)
+	assert.Equal(t, http.StatusNotModified, w.Code, "If-None-Match uses weak comparison")
+
+	w = conditionalRequest(router, "/courses", `"stale"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = conditionalRequest(router, "/cached", `"handler"`)
+	assert.Equal(t, http.StatusNotModified, w.Code, "ETags set by handlers are kept")
+
+	w = conditionalRequest(router, "/error", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"), "errors have no ETag")
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/logger"
 	"github.com/Projeto-USPY/uspy-backend/utils"
 	"github.com/golang-jwt/jwt"
 
@@ -31,5 +32,8 @@ func JWT() gin.HandlerFunc {
 
 		ctx.Set("access_token", token)
 		ctx.Set("userID", userID)
+
+		// the user claim is the NUSP, which must never be logged, so users are identified by its hash
+		setLogger(ctx, logger.FromContext(ctx).With(logger.Fields{"user": utils.SHA256(userID)}))
 	}
 }
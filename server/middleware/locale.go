@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/Projeto-USPY/uspy-backend/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageCookie holds the language the user chose in the front-end, it takes precedence over Accept-Language
+const LanguageCookie = "lang"
+
+// Locale is a middleware that picks the language of error messages, stored as i18n.ContextKey
+//
+// The user's preference in LanguageCookie is used if supported, then Accept-Language, then i18n.Default.
+// Only error messages are translated, so responses carry no Content-Language
+func Locale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		lang, ok := i18n.Language(""), false
+		if cookie, err := ctx.Cookie(LanguageCookie); err == nil {
+			lang, ok = i18n.Parse(cookie)
+		}
+
+		if !ok {
+			lang = i18n.Negotiate(ctx.GetHeader("Accept-Language"))
+		}
+
+		ctx.Set(i18n.ContextKey, lang)
+		ctx.Next()
+	}
+}
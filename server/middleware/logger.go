@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setLogger makes l the logger of the request, for handlers holding either the gin context or the request's context
+func setLogger(ctx *gin.Context, l *logger.Logger) {
+	ctx.Set(logger.ContextKey, l)
+	ctx.Request = ctx.Request.WithContext(logger.NewContext(ctx.Request.Context(), l))
+}
+
+// Logger is a middleware that writes an entry for each request, with the errors attached to its context
+//
+// It must run after RequestID, every entry written through logger.FromContext during the request carries its ID
+// (and trace ID, if the request is traced). Paths are logged by route template, so query strings and path parameters never reach the logs
+func Logger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		fields := logger.Fields{"request_id": ctx.GetString("requestID")}
+		if span := trace.SpanContextFromContext(ctx.Request.Context()); span.IsValid() {
+			fields["trace_id"] = span.TraceID().String()
+		}
+		setLogger(ctx, logger.Default().With(fields))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		fields = logger.Fields{
+			"method":     ctx.Request.Method,
+			"route":      ctx.FullPath(),
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+		}
+
+		if len(ctx.Errors) > 0 {
+			errs := make([]string, 0, len(ctx.Errors))
+			for _, err := range ctx.Errors {
+				errs = append(errs, logger.Redact(err.Error()))
+			}
+			fields["errors"] = errs
+		}
+
+		// the logger may have been replaced during the request, e.g. by JWT
+		l := logger.FromContext(ctx)
+		switch {
+		case status >= http.StatusInternalServerError:
+			l.Error("request failed", fields)
+		case status >= http.StatusBadRequest:
+			l.Warn("request rejected", fields)
+		default:
+			l.Info("request served", fields)
+		}
+	}
+}
+
+// Recovery is a middleware that logs panics with the request's logger and responds with 500
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(ctx *gin.Context, recovered interface{}) {
+		logger.FromContext(ctx).Error("recovered from panic", logger.Fields{
+			"panic": fmt.Sprint(recovered),
+			"stack": string(debug.Stack()),
+		})
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	previous := logger.Default()
+	logger.SetDefault(logger.New(&out, logger.LevelInfo, logger.FormatJSON))
+	defer logger.SetDefault(previous)
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Logger(), middleware.Recovery())
+	router.GET("/subject/:code", func(ctx *gin.Context) {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("projects/uspy/databases/(default)/documents/subjects/abc not found"))
+	})
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	lastEntry := func() map[string]interface{} {
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+		return entry
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/subject/SSC0101?secret=1", nil)
+	req.Header.Set(middleware.RequestIDHeader, "frontend-1234")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := lastEntry()
+	assert.Equal(t, "ERROR", entry["severity"])
+	assert.Equal(t, "frontend-1234", entry["request_id"])
+	assert.Equal(t, "/subject/:code", entry["route"], "paths are logged by template")
+	assert.EqualValues(t, http.StatusInternalServerError, entry["status"])
+	assert.Equal(t, []interface{}{"subjects/abc not found"}, entry["errors"])
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, out.String(), `"panic":"boom"`)
+	assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), lastEntry()["request_id"])
+}
+
+func TestLoggerTraceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	previous := logger.Default()
+	logger.SetDefault(logger.New(&out, logger.LevelInfo, logger.FormatJSON))
+	defer logger.SetDefault(previous)
+
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previousPropagator)
+
+	router := gin.New()
+	router.Use(otelgin.Middleware("test"), middleware.RequestID(), middleware.Logger())
+	router.GET("/", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a1c5e1b6d3c2f1a0-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "4bf92f3577b34da6a1c5e1b6d3c2f1a0", entry["trace_id"], "traces started by clients are continued")
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics is a middleware that observes each request in metrics.HTTPRequestDuration
+//
+// Requests that match no route are labeled "unmatched", so unknown paths cannot grow the number of series
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
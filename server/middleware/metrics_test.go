@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Projeto-USPY/uspy-backend/metrics"
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.Metrics())
+	router.GET("/subject/:code", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/metrics", middleware.BearerToken("secret"), gin.WrapH(metrics.Handler()))
+
+	before := testutil.CollectAndCount(metrics.HTTPRequestDuration)
+	utils.MakeRequest(router, http.MethodGet, "/subject/SSC0101", nil)
+	utils.MakeRequest(router, http.MethodGet, "/subject/SSC0102", nil)
+	utils.MakeRequest(router, http.MethodGet, "/random/path", nil)
+	assert.Equal(t, before+2, testutil.CollectAndCount(metrics.HTTPRequestDuration), "one series for the template, one for unmatched paths")
+
+	w := utils.MakeRequest(router, http.MethodGet, "/metrics", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `uspy_http_request_duration_seconds_count{method="GET",route="/subject/:code",status="200"} 2`)
+	assert.Contains(t, w.Body.String(), `uspy_signups_total{outcome="captcha_failure"} 0`)
+}
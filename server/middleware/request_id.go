@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID, clients and proxies may set it to correlate their own logs
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID keeps client provided IDs short and safe to log
+var validRequestID = regexp.MustCompile(`^[a-zA-Z0-9\-_.]{1,64}$`)
+
+// RequestID is a middleware that identifies each request, the ID is stored as "requestID" and sent back in X-Request-ID
+//
+// The ID is also kept in the request's context, see logger.RequestID
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		ctx.Set("requestID", id)
+		ctx.Request = ctx.Request.WithContext(logger.WithRequestID(ctx.Request.Context(), id))
+		trace.SpanFromContext(ctx.Request.Context()).SetAttributes(attribute.String("http.request_id", id))
+		ctx.Header(RequestIDHeader, id)
+		ctx.Next()
+	}
+}
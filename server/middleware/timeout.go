@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutWriter turns server errors written after the request deadline into 504 responses
+//
+// Handlers abort with 500 whenever a database call fails, so this keeps timeouts consistent without changing every handler
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		code = http.StatusGatewayTimeout
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Timeout is a middleware that sets a deadline on the request's context
+//
+// Database operations use this context, so they are cancelled once the deadline passes and the request fails with 504
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Writer = &timeoutWriter{ResponseWriter: ctx.Writer, ctx: reqCtx}
+		ctx.Next()
+	}
+}
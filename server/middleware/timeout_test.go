@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.Timeout(10 * time.Millisecond))
+	router.GET("/slow", func(ctx *gin.Context) {
+		<-ctx.Request.Context().Done()
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+	router.GET("/fast", func(ctx *gin.Context) {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+
+	w := utils.MakeRequest(router, http.MethodGet, "/slow", nil)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code, "errors after the deadline are timeouts")
+
+	w = utils.MakeRequest(router, http.MethodGet, "/fast", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "errors before the deadline are kept")
+}
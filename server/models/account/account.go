@@ -5,25 +5,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"reflect"
 	"sync"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/Projeto-USPY/uspy-backend/config"
 	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
 	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
 	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/Projeto-USPY/uspy-backend/i18n"
 	"github.com/Projeto-USPY/uspy-backend/iddigital"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/Projeto-USPY/uspy-backend/metrics"
 	"github.com/Projeto-USPY/uspy-backend/server/views/account"
+	"github.com/Projeto-USPY/uspy-backend/tracing"
 	"github.com/Projeto-USPY/uspy-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -38,9 +40,13 @@ type operation struct {
 	err error
 }
 
-func InsertUser(DB db.Env, newUser *models.User, data *iddigital.Transcript) error {
-	_, err := DB.Restore("users", newUser.Hash())
-	if status.Code(err) == codes.NotFound {
+func InsertUser(DB db.Env, newUser *models.User, data *iddigital.Transcript) (err error) {
+	ctx, span := tracing.Start(DB.Ctx, "account.InsertUser", attribute.Int("transcript.records", len(data.Grades)))
+	DB = DB.WithContext(ctx)
+	defer func() { tracing.End(span, err) }()
+
+	exists, err := db.Exists(DB, "users", newUser.Hash())
+	if err == nil && !exists {
 		// user is new
 		objs := []db.Object{
 			{
@@ -58,6 +64,11 @@ func InsertUser(DB db.Env, newUser *models.User, data *iddigital.Transcript) err
 			Data:       major,
 		})
 
+		subjectOfferings, err := getTranscriptOfferings(DB, data)
+		if err != nil {
+			return err
+		}
+
 		for _, g := range data.Grades {
 			rec := models.Record{
 				Grade:     g.Grade,
@@ -76,20 +87,16 @@ func InsertUser(DB db.Env, newUser *models.User, data *iddigital.Transcript) err
 				Data:       rec,
 			})
 
-			// add grade to "global" grades collection
-			gradeObj := models.Record{
-				Grade: g.Grade,
-			}
-
+			// add anonymous grade to "global" grades collection
 			objs = append(objs, db.Object{
 				Collection: "subjects/" + subHash + "/grades",
-				Data:       gradeObj,
+				Data:       models.NewGradeFromRecord(rec, subjectOfferings[subHash]),
 			})
 		}
 
 		// write atomically
 		if writeErr := DB.BatchWrite(objs); writeErr != nil {
-			return err
+			return writeErr
 		}
 	} else if err != nil {
 		return err
@@ -100,6 +107,53 @@ func InsertUser(DB db.Env, newUser *models.User, data *iddigital.Transcript) err
 	return nil
 }
 
+// getTranscriptOfferings fetches the offerings of every subject in the transcript, keyed by subject hash
+//
+// Offerings are used to find out who taught the subject when the user took it. Each subject is read once and all reads
+// run concurrently, so signup latency does not grow with the size of the transcript
+func getTranscriptOfferings(DB db.Env, data *iddigital.Transcript) (map[string]map[string]*models.Offering, error) {
+	subjects := make(map[string]string)
+	for _, g := range data.Grades {
+		subHash := models.Subject{Code: g.Subject, CourseCode: g.Course, Specialization: g.Specialization}.Hash()
+		subjects[subHash] = g.Subject
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		fetchErr error
+	)
+
+	subjectOfferings := make(map[string]map[string]*models.Offering, len(subjects))
+	for subHash, code := range subjects {
+		wg.Add(1)
+		go func(subHash, code string) {
+			defer wg.Done()
+
+			offerings, err := db_utils.GetSubjectOfferings(DB, subHash)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if fetchErr == nil {
+					fetchErr = fmt.Errorf("failed to fetch offerings of subject %s: %s", code, err.Error())
+				}
+				return
+			}
+
+			subjectOfferings[subHash] = offerings
+		}(subHash, code)
+	}
+
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	return subjectOfferings, nil
+}
+
 func sendPasswordRecoveryEmail(email, userHash string) error {
 	if config.Env.IsLocal() || config.Env.IsDev() {
 		return nil
@@ -115,14 +169,7 @@ func sendPasswordRecoveryEmail(email, userHash string) error {
 		return err
 	}
 
-	var host string
-	if config.Env.Mode == "dev" {
-		host = "frontdev.uspy.me"
-	} else {
-		host = "uspy.me"
-	}
-
-	url := fmt.Sprintf(`https://%s/account/password_reset?token=%s`, host, token)
+	url := fmt.Sprintf(`%s/account/password_reset?token=%s`, config.Env.FrontendURL, token)
 	content := fmt.Sprintf(config.PasswordRecoveryContent, url)
 	return config.Env.Send(email, config.PasswordRecoverySubject, content)
 }
@@ -144,35 +191,21 @@ func sendEmailVerification(email, userHash string) error {
 		return err
 	}
 
-	var host string
-	if config.Env.Mode == "dev" {
-		host = "frontdev.uspy.me"
-	} else {
-		host = "uspy.me"
-	}
-
-	url := fmt.Sprintf(`https://%s/account/verify?token=%s`, host, token)
+	url := fmt.Sprintf(`%s/account/verify?token=%s`, config.Env.FrontendURL, token)
 	content := fmt.Sprintf(config.VerificationContent, url)
 	return config.Env.Send(email, config.VerificationSubject, content)
 }
 
 // Profile retrieves the user profile from the database
 func Profile(ctx *gin.Context, DB db.Env, userID string) {
-	var storedUser models.User
-
-	snap, err := DB.Restore("users", utils.SHA256(userID))
+	storedUser, err := db.Get[models.User](DB, "users", utils.SHA256(userID))
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get user with id %s: %s", userID, err.Error()))
-		return
-	}
-	err = snap.DataTo(&storedUser)
-	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to bind user %s data to model: %s", userID, err.Error()))
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get user %s: %s", utils.SHA256(userID), err.Error()))
 		return
 	}
 
 	storedUser.ID = userID
-	account.Profile(ctx, storedUser)
+	account.Profile(ctx, *storedUser)
 }
 
 // Signup inserts a new user into the DB
@@ -180,42 +213,46 @@ func Signup(ctx *gin.Context, DB db.Env, signupForm *controllers.SignupForm) {
 	// check if email already exists in the database
 	hashedEmail := utils.SHA256(signupForm.Email)
 	query := DB.Client.Collection("users").Where("email", "==", hashedEmail).Limit(1)
-	snaps, err := query.Documents(ctx).GetAll()
+	snaps, err := query.Documents(DB.Ctx).GetAll()
 
 	if err != nil || len(snaps) != 0 {
-		ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrInvalidEmail)
+		if err != nil {
+			metrics.Signup(metrics.SignupError)
+		} else {
+			metrics.Signup(metrics.SignupDuplicateEmail)
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrInvalidEmail.In(i18n.FromContext(ctx)))
 		return
 	}
 
 	// get user records
 	cookies := ctx.Request.Cookies()
-	resp, err := iddigital.PostAuthCode(signupForm.AccessKey, signupForm.Captcha, cookies)
+	resp, err := iddigital.PostAuthCode(ctx.Request.Context(), signupForm.AccessKey, signupForm.Captcha, cookies)
 	if err != nil {
+		metrics.Signup(metrics.SignupIddigitalError)
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error getting pdf from iddigital: %s", err.Error()))
 		return
 	} else if resp.Header.Get("Content-Type") != "application/pdf" {
+		metrics.Signup(metrics.SignupCaptchaFailure)
 		ctx.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
 	// parse transcript
-	if pdf := iddigital.NewPDF(resp); pdf.Error != nil {
+	if pdf := iddigital.NewPDF(ctx.Request.Context(), resp); pdf.Error != nil {
+		metrics.Signup(metrics.SignupParseError)
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error converting pdf to text: %s", pdf.Error.Error()))
 		return
 	} else {
 		data, err := pdf.Parse(DB)
 
-		var maxPDFAge float64
-		if config.Env.Mode == "dev" {
-			maxPDFAge = 24 * 30 // a month
-		} else {
-			maxPDFAge = 1.0 // an hour
-		}
-
 		if err != nil {
+			metrics.Signup(metrics.SignupParseError)
 			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error parsing pdf: %s", err.Error()))
 			return
-		} else if time.Since(pdf.CreationDate).Hours() > maxPDFAge {
+		} else if time.Since(pdf.CreationDate) > config.Env.MaxPDFAge {
+			metrics.Signup(metrics.SignupPDFTooOld)
 			ctx.AbortWithStatus(http.StatusBadRequest)
 			return
 		}
@@ -230,6 +267,7 @@ func Signup(ctx *gin.Context, DB db.Env, signupForm *controllers.SignupForm) {
 		)
 
 		if userErr != nil {
+			metrics.Signup(metrics.SignupError)
 			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error generating user: %s", userErr.Error()))
 			return
 		}
@@ -237,20 +275,24 @@ func Signup(ctx *gin.Context, DB db.Env, signupForm *controllers.SignupForm) {
 		// insert user object into database
 		if err := InsertUser(DB, newUser, &data); err != nil {
 			if err == ErrUserExists {
-				ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrInvalidUser)
+				metrics.Signup(metrics.SignupDuplicateUser)
+				ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrInvalidUser.In(i18n.FromContext(ctx)))
 				return
 			}
 
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error inserting user %s: %s", data.Nusp, err.Error()))
+			metrics.Signup(metrics.SignupError)
+			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error inserting user %s: %s", newUser.Hash(), err.Error()))
 			return
 		}
 
 		// send email verification
 		if err := sendEmailVerification(signupForm.Email, newUser.Hash()); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to send email verification to user %s; %s", signupForm.Email, err.Error()))
+			metrics.Signup(metrics.SignupError)
+			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to send email verification to user %s; %s", newUser.Hash(), err.Error()))
 			return
 		}
 
+		metrics.Signup(metrics.SignupSuccess)
 		account.Signup(ctx, newUser.ID, data)
 	}
 
@@ -258,7 +300,7 @@ func Signup(ctx *gin.Context, DB db.Env, signupForm *controllers.SignupForm) {
 
 // SignupCaptcha gets the iddigital validation captcha
 func SignupCaptcha(ctx *gin.Context) {
-	resp, err := iddigital.GetCaptcha()
+	resp, err := iddigital.GetCaptcha(ctx.Request.Context())
 	if err != nil {
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error getting captcha from iddigital: %s", err.Error()))
 		return
@@ -272,35 +314,29 @@ func SignupCaptcha(ctx *gin.Context) {
 
 // Login performs the user login by comparing the passwordHash and the stored hash
 func Login(ctx *gin.Context, DB db.Env, login *controllers.Login) {
-	if snap, err := DB.Restore("users", utils.SHA256(login.ID)); err != nil { // get user from database
-		if status.Code(err) == codes.NotFound { // if user was not found
+	if storedUser, err := db.Get[models.User](DB, "users", utils.SHA256(login.ID)); err != nil { // get user from database
+		if errors.Is(err, db.ErrNotFound) { // if user was not found
 			ctx.AbortWithError(http.StatusUnauthorized, err)
 			return
 		}
 		ctx.AbortWithError(http.StatusInternalServerError, err)
 		return
 	} else {
-		var storedUser models.User
-		if err := snap.DataTo(&storedUser); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, err) // some error happened
-			return
-		}
-
 		// check if password is correct
 		if !utils.BcryptCompare(login.Password, storedUser.PasswordHash) {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, views.ErrInvalidCredentials)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, views.ErrInvalidCredentials.In(i18n.FromContext(ctx)))
 			return
 		}
 
 		// check if user has verified their email
 		if !storedUser.Verified {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrUnverifiedUser)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrUnverifiedUser.In(i18n.FromContext(ctx)))
 			return
 		}
 
 		// check if user is banned
 		if storedUser.Banned {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrBannedUser)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrBannedUser.In(i18n.FromContext(ctx)))
 			return
 		}
 
@@ -309,7 +345,7 @@ func Login(ctx *gin.Context, DB db.Env, login *controllers.Login) {
 			"user":      login.ID,
 			"timestamp": time.Now().Unix(),
 		}, config.Env.JWTSecret); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error generating jwt for user %s: %s", storedUser.ID, err.Error()))
+			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error generating jwt for user %s: %s", utils.SHA256(storedUser.ID), err.Error()))
 			return
 		} else {
 			domain := ctx.MustGet("front_domain").(string)
@@ -342,23 +378,17 @@ func Logout(ctx *gin.Context) {
 // ChangePassword changes the user's password in the database
 // This method requires the user to be logged in
 func ChangePassword(ctx *gin.Context, DB db.Env, userID string, resetForm *controllers.PasswordChange) {
-	if snap, err := DB.Restore("users", utils.SHA256(userID)); err != nil {
-		if status.Code(err) == codes.NotFound { // if user was not found
+	if storedUser, err := db.Get[models.User](DB, "users", utils.SHA256(userID)); err != nil {
+		if errors.Is(err, db.ErrNotFound) { // if user was not found
 			ctx.AbortWithError(http.StatusForbidden, err)
 			return
 		}
 		ctx.AbortWithError(http.StatusInternalServerError, err)
 		return
 	} else {
-		var storedUser models.User
-		if err := snap.DataTo(&storedUser); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, err)
-			return
-		}
-
 		// check if old password is correct
 		if !utils.BcryptCompare(resetForm.OldPassword, storedUser.PasswordHash) {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrWrongPassword)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, views.ErrWrongPassword.In(i18n.FromContext(ctx)))
 			return
 		}
 
@@ -389,18 +419,14 @@ func ResetPassword(ctx *gin.Context, DB db.Env, recovery *controllers.PasswordRe
 	claims := token.Claims.(jwt.MapClaims)
 	userHash := claims["user"].(string)
 
-	var storedUser models.User
-
 	// assert user exists
-	if snap, err := DB.Restore("users", userHash); err != nil {
-		if status.Code(err) == codes.NotFound { // if user was not found
+	storedUser, err := db.Get[models.User](DB, "users", userHash)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) { // if user was not found
 			ctx.AbortWithError(http.StatusNotFound, err)
 			return
 		}
 
-		ctx.AbortWithError(http.StatusInternalServerError, err)
-		return
-	} else if err := snap.DataTo(&storedUser); err != nil {
 		ctx.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
@@ -444,10 +470,10 @@ func VerifyAccount(ctx *gin.Context, DB db.Env, verification *controllers.Accoun
 
 // Delete deletes the given user (removing all of its traces (grades, reviews, etc)
 func Delete(ctx *gin.Context, DB db.Env, userID string) {
-	if deleteErr := DB.Client.RunTransaction(DB.Ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+	if deleteErr := db.RunTransaction(DB, "delete account", func(ctx context.Context, tx *firestore.Transaction) error {
 		objects := getUserObjects(DB, ctx, tx, userID)
 
-		log.Printf("user %s is removing their account, total objects affected: %v\n", userID, len(objects))
+		logger.FromContext(ctx).Info("user is removing their account", logger.Fields{"user": utils.SHA256(userID), "objects": len(objects)})
 
 		for _, obj := range objects {
 			if obj.err != nil {
@@ -460,6 +486,8 @@ func Delete(ctx *gin.Context, DB db.Env, userID string) {
 				operationErr = tx.Delete(obj.ref)
 			case "update":
 				operationErr = tx.Update(obj.ref, obj.payload.([]firestore.Update))
+			case "merge": // used by sharded counters
+				operationErr = tx.Set(obj.ref, obj.payload, firestore.MergeAll)
 			}
 
 			if operationErr != nil {
@@ -547,56 +575,87 @@ func getScoreObjects(
 				return
 			}
 
-			wg.Add(len(recordsDocs))
+			// grades of the same subject are resolved together, so no grade is claimed by two records
+			grades := DB.Client.Collection(fmt.Sprintf(
+				"subjects/%s/grades",
+				scoreRef.ID,
+			))
+
+			claimed := make(map[string]bool)
 			for _, recordRef := range recordsDocs {
-				go func(recordRef *firestore.DocumentRef) {
-					defer wg.Done()
-
-					// insert record in channel
-					objects <- operation{
-						ref:    recordRef,
-						method: "delete",
-					}
-
-					// get record value
-					recordSnap, err := tx.Get(recordRef)
-					if err != nil {
-						objects <- operation{err: errors.New("failed to convert record to snap: " + err.Error())}
-						return
-					}
-
-					gradeValue, err := recordSnap.DataAt("grade")
-					if err != nil {
-						objects <- operation{err: errors.New("failed to get grade value from snap at field grade: " + err.Error())}
-						return
-					}
-
-					// query object with same value in subject grades
-					grades := DB.Client.Collection(fmt.Sprintf(
-						"subjects/%s/grades",
-						scoreRef.ID,
-					))
-
-					query := grades.Where("grade", "==", gradeValue).Limit(1)
-					subjectRecordSnaps, err := tx.Documents(query).GetAll()
-					if err != nil {
-						objects <- operation{err: errors.New("failed to get queried grades from subject: " + err.Error())}
-						return
-					}
-
-					// insert subject grades in channel
-					for _, ref := range subjectRecordSnaps {
-						objects <- operation{
-							ref:    ref.Ref,
-							method: "delete",
-						}
-					}
-				}(recordRef)
+				// insert record in channel
+				objects <- operation{
+					ref:    recordRef,
+					method: "delete",
+				}
+
+				recordSnap, err := tx.Get(recordRef)
+				if err != nil {
+					objects <- operation{err: errors.New("failed to convert record to snap: " + err.Error())}
+					return
+				}
+
+				var rec models.Record
+				if err := recordSnap.DataTo(&rec); err != nil {
+					objects <- operation{err: errors.New("failed to bind record: " + err.Error())}
+					return
+				}
+
+				gradeRef, err := findRecordGrade(tx, grades, rec, claimed)
+				if err != nil {
+					objects <- operation{err: errors.New("failed to get queried grades from subject: " + err.Error())}
+					return
+				} else if gradeRef == nil {
+					continue
+				}
+
+				// insert subject grade in channel
+				claimed[gradeRef.ID] = true
+				objects <- operation{
+					ref:    gradeRef,
+					method: "delete",
+				}
 			}
 		}(scoreRef)
 	}
 }
 
+// findRecordGrade finds the anonymous subject grade that was created from a user record
+//
+// Grades have the year, semester and value of their record. Older grades only have the value, so they are matched by
+// it when no grade has the record's year and semester. Grades in claimed were already matched to other records
+func findRecordGrade(tx *firestore.Transaction, grades *firestore.CollectionRef, rec models.Record, claimed map[string]bool) (*firestore.DocumentRef, error) {
+	query := grades.Where("grade", "==", rec.Grade).Where("year", "==", rec.Year).Where("semester", "==", rec.Semester)
+	snaps, err := tx.Documents(query).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snap := range snaps {
+		if !claimed[snap.Ref.ID] {
+			return snap.Ref, nil
+		}
+	}
+
+	snaps, err = tx.Documents(grades.Where("grade", "==", rec.Grade)).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snap := range snaps {
+		var grade models.Grade
+		if err := snap.DataTo(&grade); err != nil {
+			return nil, err
+		}
+
+		if grade.Year == 0 && !claimed[snap.Ref.ID] {
+			return snap.Ref, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func getReviewObjects(
 	DB db.Env, ctx context.Context,
 	tx *firestore.Transaction,
@@ -632,33 +691,18 @@ func getReviewObjects(
 				return
 			}
 
-			// lookup subjects that need to be updated
-			categories, err := reviewSnap.DataAt("categories") // get existing review categories
-			if err != nil {
-				objects <- operation{err: errors.New("failed to get review snap at field categories: " + err.Error())}
+			var review models.SubjectReview
+			if err := reviewSnap.DataTo(&review); err != nil {
+				objects <- operation{err: errors.New("failed to bind review snap: " + err.Error())}
 				return
 			}
 
-			subRef := DB.Client.Doc("subjects/" + reviewSnap.Ref.ID)
-
-			// iterate through categories and look which need to have stats decreased
-			for k, v := range categories.(map[string]interface{}) {
-				// decrement every category review which was true
-				if reflect.ValueOf(v).Kind() == reflect.Bool && v.(bool) {
-					path := fmt.Sprintf("stats.%s", k)
-					objects <- operation{
-						ref:     subRef,
-						method:  "update",
-						payload: []firestore.Update{{Path: path, Value: firestore.Increment(-1)}},
-					}
-				}
-			}
-
-			// number of total reviews must also be decreased
+			// remove the review from the subject stats
+			shard, data := models.StatsCounter(DB, reviewSnap.Ref.ID).IncrementData(models.ReviewStatsUpdates(&review, nil))
 			objects <- operation{
-				ref:     subRef,
-				method:  "update",
-				payload: []firestore.Update{{Path: "stats.total", Value: firestore.Increment(-1)}},
+				ref:     shard,
+				method:  "merge",
+				payload: data,
 			}
 		}(reviewRef)
 	}
@@ -721,6 +765,21 @@ func getCommentObjects(
 				ref:    commentRef,
 				method: "delete",
 			}
+
+			// remove the comment rating from the offering stats
+			objects <- operation{
+				ref:     commentRef.Parent.Parent,
+				method:  "update",
+				payload: models.CommentStatsUpdates(&userComment.Comment, nil),
+			}
+
+			// remove the comment votes
+			for _, shard := range models.VotesCounter(DB, subject.Hash(), userComment.ProfessorHash, userRef.ID).ShardRefs() {
+				objects <- operation{
+					ref:    shard,
+					method: "delete",
+				}
+			}
 		}(userCommentRef)
 	}
 }
@@ -782,38 +841,18 @@ func getCommentRatingObjects(
 			} else if len(commentSnaps) == 0 { // this comment does not exist anymore
 				return
 			} else {
-				var path string
-
-				if commentRating.Upvote { // decrease original comment upvote count
+				path := "downvotes"
+				if commentRating.Upvote {
 					path = "upvotes"
-				} else { // decrease original comment downvote count
-					path = "downvotes"
 				}
 
+				// decrease original comment vote count, the author's replica is reconciled by the consistency job
+				counter := models.VotesCounter(DB, subject.Hash(), commentRating.ProfessorHash, commentSnaps[0].Ref.ID)
+				shard, data := counter.IncrementData([]firestore.Update{{Path: path, Value: firestore.Increment(-1)}})
 				objects <- operation{
-					ref:     commentSnaps[0].Ref,
-					method:  "update",
-					payload: []firestore.Update{{Path: path, Value: firestore.Increment(-1)}},
-				}
-
-				targetUserComment := models.UserComment{
-					ProfessorHash:  commentRating.ProfessorHash,
-					Subject:        commentRating.Subject,
-					Course:         commentRating.Course,
-					Specialization: commentRating.Specialization,
-				}
-
-				// user comment should receive same update
-				targetUserCommentRef := DB.Client.Doc(fmt.Sprintf(
-					"users/%s/user_comments/%s",
-					commentSnaps[0].Ref.ID,
-					targetUserComment.Hash(),
-				))
-
-				objects <- operation{
-					ref:     targetUserCommentRef,
-					method:  "update",
-					payload: []firestore.Update{{Path: "comment." + path, Value: firestore.Increment(-1)}},
+					ref:     shard,
+					method:  "merge",
+					payload: data,
 				}
 			}
 
@@ -1,6 +1,7 @@
 package account
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -16,7 +17,7 @@ import (
 func VerifyEmail(ctx *gin.Context, DB db.Env, emailForm *controllers.EmailVerificationSubmission) {
 	// check if email exists and if it's not already verified
 	emailHash := utils.SHA256(emailForm.Email)
-	docs := DB.Client.Collection("users").Where("email", "==", emailHash).Limit(1).Documents(ctx)
+	docs := DB.Client.Collection("users").Where("email", "==", emailHash).Limit(1).Documents(DB.Ctx)
 	var user models.User
 
 	snaps, err := docs.GetAll()
@@ -25,7 +26,7 @@ func VerifyEmail(ctx *gin.Context, DB db.Env, emailForm *controllers.EmailVerifi
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not find email to resend verification:  %s", err.Error()))
 		return
 	} else if len(snaps) == 0 { // user not found
-		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("email %s not found in database", emailForm.Email))
+		ctx.AbortWithError(http.StatusNotFound, errors.New("email not found in database"))
 		return
 	} else if err := snaps[0].DataTo(&user); err != nil {
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error binding user to user object: %s", err.Error()))
@@ -39,7 +40,7 @@ func VerifyEmail(ctx *gin.Context, DB db.Env, emailForm *controllers.EmailVerifi
 
 	// send email
 	if err := sendEmailVerification(emailForm.Email, snaps[0].Ref.ID); err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to send email verification to user %s; %s", emailForm.Email, err.Error()))
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to send email verification: %s", err.Error()))
 		return
 	}
 
@@ -50,7 +51,7 @@ func VerifyEmail(ctx *gin.Context, DB db.Env, emailForm *controllers.EmailVerifi
 func RequestPasswordReset(ctx *gin.Context, DB db.Env, form *controllers.EmailVerificationSubmission) {
 	// check if email exists
 	emailHash := utils.SHA256(form.Email)
-	docs := DB.Client.Collection("users").Where("email", "==", emailHash).Limit(1).Documents(ctx)
+	docs := DB.Client.Collection("users").Where("email", "==", emailHash).Limit(1).Documents(DB.Ctx)
 	var user models.User
 
 	snaps, err := docs.GetAll()
@@ -59,7 +60,7 @@ func RequestPasswordReset(ctx *gin.Context, DB db.Env, form *controllers.EmailVe
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not find email to resend verification:  %s", err.Error()))
 		return
 	} else if len(snaps) == 0 { // user not found
-		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("email %s not found in database", form.Email))
+		ctx.AbortWithError(http.StatusNotFound, errors.New("email not found in database"))
 		return
 	} else if err := snaps[0].DataTo(&user); err != nil {
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error binding user to user object: %s", err.Error()))
@@ -68,7 +69,7 @@ func RequestPasswordReset(ctx *gin.Context, DB db.Env, form *controllers.EmailVe
 
 	// send email
 	if err := sendPasswordRecoveryEmail(form.Email, snaps[0].Ref.ID); err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to send password recovery email to user %s; %s", form.Email, err.Error()))
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to send password recovery email: %s", err.Error()))
 		return
 	}
 
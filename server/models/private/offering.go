@@ -2,9 +2,9 @@ package private
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"sync"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -28,9 +28,9 @@ func GetComment(ctx *gin.Context, DB db.Env, userID string, off *controllers.Off
 		Specialization: off.Subject.Specialization,
 	}.Hash()
 
-	var comment models.Comment
-	if snap, err := DB.Restore(fmt.Sprintf(mask, subHash, off.Hash), userHash); err != nil {
-		if status.Code(err) == codes.NotFound {
+	comment, err := db.Get[models.Comment](DB, fmt.Sprintf(mask, subHash, off.Hash), userHash)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
 		}
@@ -40,14 +40,16 @@ func GetComment(ctx *gin.Context, DB db.Env, userID string, off *controllers.Off
 			fmt.Errorf("error getting comment: (sub:%s/%s, user:%s): %s", subHash, off.Hash, userHash, err.Error()),
 		)
 		return
-	} else {
-		if err := snap.DataTo(&comment); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error binding comment: %s", err.Error()))
-			return
-		}
 	}
 
-	private.GetComment(ctx, &comment)
+	votes, err := models.VotesCounter(DB, subHash, off.Hash, userHash).Read(DB.Ctx)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error reading comment votes: %s", err.Error()))
+		return
+	}
+	comment.AddVotes(votes)
+
+	private.GetComment(ctx, comment)
 }
 
 func GetCommentRating(
@@ -60,24 +62,19 @@ func GetCommentRating(
 		ID: userID,
 	}.Hash()
 
-	var model models.CommentRating
 	collectionMask := "users/%s/comment_ratings"
-	if snap, err := DB.Restore(fmt.Sprintf(collectionMask, userHash), comment.ID); err != nil {
-		if status.Code(err) == codes.NotFound {
+	model, err := db.Get[models.CommentRating](DB, fmt.Sprintf(collectionMask, userHash), comment.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
-		} else {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error looking up comment rating: %s", err.Error()))
-			return
-		}
-	} else {
-		if snap.DataTo(&model); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error binding comment: %s", err.Error()))
-			return
 		}
+
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error looking up comment rating: %s", err.Error()))
+		return
 	}
 
-	private.GetCommentRating(ctx, &model)
+	private.GetCommentRating(ctx, model)
 }
 
 func RateComment(
@@ -96,14 +93,7 @@ func RateComment(
 		ID: userID,
 	}.Hash()
 
-	userCommentHash := models.UserComment{
-		ProfessorHash:  comment.Offering.Hash,
-		Subject:        comment.Offering.Subject.Code,
-		Course:         comment.Offering.Subject.CourseCode,
-		Specialization: comment.Offering.Subject.Specialization,
-	}.Hash()
-
-	err := DB.Client.RunTransaction(ctx, func(txCtx context.Context, tx *firestore.Transaction) error {
+	err := db.RunTransaction(DB, "rate comment", func(txCtx context.Context, tx *firestore.Transaction) error {
 		commentsCol := "subjects/%s/offerings/%s/comments"
 		target := DB.Client.Collection(
 			fmt.Sprintf(commentsCol, subHash, comment.Offering.Hash),
@@ -138,17 +128,9 @@ func RateComment(
 			Specialization: comment.Offering.Subject.Specialization,
 		}
 
-		type update struct {
-			ref     *firestore.DocumentRef
-			changes []firestore.Update
-		}
-
-		updates := make([]update, 0, 10)
-
-		replicaMask := "users/%s/user_comments/%s"
-		replicaRef := DB.Client.Doc(fmt.Sprintf(replicaMask, targetRef.ID, userCommentHash))
+		votes := make(map[string]int)
 
-		// if rating already exists and it's different, we add the decrement updates for the comment and replica's count
+		// if rating already exists and it's different, we remove the stored vote from the comment's count
 		if ratingDoc, err := tx.Get(ratingRef); err == nil {
 			storedUpvote, err := ratingDoc.DataAt("upvote")
 
@@ -159,107 +141,38 @@ func RateComment(
 				return err
 			}
 
-			// if rating changed and it's none (user removed their rating)
-			if body.Type == "none" {
-				if storedUpvote.(bool) {
-					updates = append(updates,
-						update{
-							ref:     targetRef,
-							changes: []firestore.Update{{Path: "upvotes", Value: firestore.Increment(-1)}},
-						},
-						update{
-							ref:     replicaRef,
-							changes: []firestore.Update{{Path: "comment.upvotes", Value: firestore.Increment(-1)}},
-						},
-					)
-				} else {
-					updates = append(updates,
-						update{
-							ref:     targetRef,
-							changes: []firestore.Update{{Path: "downvotes", Value: firestore.Increment(-1)}},
-						},
-						update{
-							ref:     replicaRef,
-							changes: []firestore.Update{{Path: "comment.downvotes", Value: firestore.Increment(-1)}},
-						},
-					)
-				}
-			} else { // rating changed to the opposite type
-				if commentRating.Upvote {
-					updates = append(updates,
-						update{
-							ref:     targetRef,
-							changes: []firestore.Update{{Path: "downvotes", Value: firestore.Increment(-1)}},
-						},
-						update{
-							ref:     replicaRef,
-							changes: []firestore.Update{{Path: "comment.downvotes", Value: firestore.Increment(-1)}},
-						},
-					)
-				} else {
-					updates = append(updates,
-						update{
-							ref:     targetRef,
-							changes: []firestore.Update{{Path: "upvotes", Value: firestore.Increment(-1)}},
-						},
-						update{
-							ref:     replicaRef,
-							changes: []firestore.Update{{Path: "comment.upvotes", Value: firestore.Increment(-1)}},
-						},
-					)
-				}
+			if storedUpvote.(bool) {
+				votes["upvotes"]--
+			} else {
+				votes["downvotes"]--
 			}
-
 		} else if status.Code(err) != codes.NotFound {
 			return err
 		}
 
-		// now we must add the updates to increment the comment and replica's count (only if the type isnt none)
+		// now we must add the new vote to the comment's count (only if the type isnt none)
 		if body.Type != "none" {
 			if commentRating.Upvote {
-				updates = append(updates,
-					update{
-						ref:     targetRef,
-						changes: []firestore.Update{{Path: "upvotes", Value: firestore.Increment(1)}},
-					},
-					update{
-						ref:     replicaRef,
-						changes: []firestore.Update{{Path: "comment.upvotes", Value: firestore.Increment(1)}},
-					},
-				)
+				votes["upvotes"]++
 			} else {
-				updates = append(updates,
-					update{
-						ref:     targetRef,
-						changes: []firestore.Update{{Path: "downvotes", Value: firestore.Increment(1)}},
-					},
-					update{
-						ref:     replicaRef,
-						changes: []firestore.Update{{Path: "comment.downvotes", Value: firestore.Increment(1)}},
-					},
-				)
+				votes["downvotes"]++
 			}
 		}
 
-		var wg sync.WaitGroup
-		updateErrors := make(chan error, len(updates))
-		wg.Add(len(updates))
-
-		// perform updates in parallel
-		for _, u := range updates {
-			go func(upd update, wg *sync.WaitGroup) {
-				defer wg.Done()
-				updateErrors <- tx.Update(upd.ref, upd.changes)
-			}(u, &wg)
+		updates := make([]firestore.Update, 0, 2)
+		for _, path := range []string{"upvotes", "downvotes"} {
+			if votes[path] != 0 {
+				updates = append(updates, firestore.Update{Path: path, Value: firestore.Increment(votes[path])})
+			}
 		}
 
-		wg.Wait()
-		close(updateErrors)
-
-		for err := range updateErrors {
-			if err != nil {
-				return err
-			}
+		// votes are kept in a sharded counter, since popular comments receive many of them at once
+		//
+		// the author's replica in user_comments is not touched here, as it would be a single hot document again.
+		// Its votes are reconciled by the consistency job (see admin.CheckVotes)
+		counter := models.VotesCounter(DB, subHash, comment.Offering.Hash, targetRef.ID)
+		if err := counter.Increment(tx, updates); err != nil {
+			return err
 		}
 
 		// upsert comment rating if type isnt none
@@ -309,7 +222,7 @@ func ReportComment(
 		Specialization: comment.Offering.Subject.Specialization,
 	}.Hash()
 
-	err := DB.Client.RunTransaction(ctx, func(txCtx context.Context, tx *firestore.Transaction) error {
+	err := db.RunTransaction(DB, "report comment", func(txCtx context.Context, tx *firestore.Transaction) error {
 		commentsCol := "subjects/%s/offerings/%s/comments"
 		target := DB.Client.Collection(
 			fmt.Sprintf(commentsCol, subHash, comment.Offering.Hash),
@@ -399,14 +312,14 @@ func PublishComment(
 		}
 
 		if err == db_utils.ErrNoPermission {
-			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to comment: %s", userID, err.Error()))
+			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to comment: %s", userHash, err.Error()))
 			return
 		}
 
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error checking subject permission: %s", err.Error()))
 		return
-	} else if _, err := DB.Restore("subjects/"+modelSub.Hash()+"/offerings", off.Hash); err != nil {
-		if status.Code(err) == codes.NotFound {
+	} else if _, err := db.Get[models.Offering](DB, "subjects/"+modelSub.Hash()+"/offerings", off.Hash); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
 		}
@@ -430,7 +343,7 @@ func PublishComment(
 		Reports:   0,
 	}
 
-	err := DB.Client.RunTransaction(ctx, func(txCtx context.Context, tx *firestore.Transaction) error {
+	err := db.RunTransaction(DB, "publish comment", func(txCtx context.Context, tx *firestore.Transaction) error {
 		collectionMask := "subjects/%s/offerings/%s/comments/%s"
 		commentRef := DB.Client.Doc(
 			fmt.Sprintf(
@@ -459,9 +372,6 @@ func PublishComment(
 			newComment.ID = storedComment.ID
 		}
 
-		// upsert comment in database
-		tx.Set(commentRef, newComment)
-
 		// upsert replica in user comments (will be used in the future)
 		replica := models.UserComment{
 			Comment:        newComment,
@@ -480,6 +390,31 @@ func PublishComment(
 			),
 		)
 
+		// the replica's votes are reconciled by the consistency job, so the stored ones are kept
+		if snap, err := tx.Get(replicaRef); err == nil {
+			var storedReplica models.UserComment
+			if err := snap.DataTo(&storedReplica); err != nil {
+				return err
+			}
+
+			replica.Upvotes, replica.Downvotes = storedReplica.Upvotes, storedReplica.Downvotes
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		// upsert comment in database
+		if err := tx.Set(commentRef, newComment); err != nil {
+			return err
+		}
+
+		// update offering rating stats, replacing the stored comment by the new one
+		offeringRef := DB.Client.Doc(fmt.Sprintf("subjects/%s/offerings/%s", modelSub.Hash(), off.Hash))
+		if updates := models.CommentStatsUpdates(storedComment, &newComment); len(updates) > 0 {
+			if err := tx.Update(offeringRef, updates); err != nil {
+				return err
+			}
+		}
+
 		return tx.Set(replicaRef, replica)
 	})
 
@@ -488,5 +423,12 @@ func PublishComment(
 		return
 	}
 
+	votes, err := models.VotesCounter(DB, modelSub.Hash(), off.Hash, userHash).Read(DB.Ctx)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error reading comment votes: %s", err.Error()))
+		return
+	}
+	newComment.AddVotes(votes)
+
 	private.PublishComment(ctx, &newComment)
 }
@@ -0,0 +1,47 @@
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/server/views/private"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDegreeProgress is the model implementation for /server/controller/private/progress.GetDegreeProgress
+func GetDegreeProgress(ctx *gin.Context, DB db.Env, userID string, course *controllers.Course) {
+	userHash := models.User{ID: userID}.Hash()
+	major := models.Major{Course: course.Code, Specialization: course.Specialization}
+
+	// check if user is enrolled in this major
+	if err := db_utils.CheckUserMajor(DB, userHash, major); err != nil {
+		if err == db_utils.ErrMajorNotFound {
+			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to get progress of major %v: %s", userHash, major, err.Error()))
+			return
+		}
+
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error checking user major: %s", err.Error()))
+		return
+	}
+
+	subjects, err := db_utils.GetCourseSubjects(DB, course.Code, course.Specialization)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subjects from major %v: %s", major, err.Error()))
+		return
+	} else if len(subjects) == 0 {
+		ctx.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	records, err := db_utils.GetUserRecords(DB, userHash, subjects)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch user records: %s", err.Error()))
+		return
+	}
+
+	private.GetDegreeProgress(ctx, course.Code, course.Specialization, models.NewDegreeProgress(subjects, records))
+}
@@ -3,9 +3,9 @@ package private
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
 
 	"cloud.google.com/go/firestore"
 	"github.com/Projeto-USPY/uspy-backend/db"
@@ -23,24 +23,17 @@ func GetSubjectGrade(ctx *gin.Context, DB db.Env, userID string, sub *controller
 	user, model := models.User{ID: userID}, models.NewSubjectFromController(sub)
 	userHash, subHash := user.Hash(), model.Hash()
 
-	col, err := DB.RestoreCollection("users/" + userHash + "/final_scores/" + subHash + "/records")
+	records, err := db.List[models.Record](DB, "users/"+userHash+"/final_scores/"+subHash+"/records", db.Query{})
 	if err != nil {
 		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find records: %s", err.Error()))
 		return
-	} else if len(col) == 0 {
+	} else if len(records) == 0 {
 		ctx.AbortWithStatus(http.StatusNotFound)
 		return
 	}
 
 	best := models.Record{}
-	for _, s := range col {
-		var fs models.Record
-		err := s.DataTo(&fs)
-		if err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not bind final score: %s", err.Error()))
-			return
-		}
-
+	for _, fs := range records {
 		if fs.Grade > best.Grade {
 			best = fs
 		} else if fs.Grade == best.Grade && fs.Year > best.Year {
@@ -55,7 +48,6 @@ func GetSubjectGrade(ctx *gin.Context, DB db.Env, userID string, sub *controller
 func GetSubjectReview(ctx *gin.Context, DB db.Env, userID string, sub *controllers.Subject) {
 	user, model := models.User{ID: userID}, models.NewSubjectFromController(sub)
 	userHash, subHash := user.Hash(), model.Hash()
-	review := models.SubjectReview{}
 
 	err := db_utils.CheckSubjectPermission(DB, userHash, subHash)
 	if err != nil {
@@ -65,7 +57,7 @@ func GetSubjectReview(ctx *gin.Context, DB db.Env, userID string, sub *controlle
 		}
 
 		if err == db_utils.ErrNoPermission {
-			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to get review: %s", userID, err.Error()))
+			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to get review: %s", userHash, err.Error()))
 			return
 		}
 
@@ -73,11 +65,10 @@ func GetSubjectReview(ctx *gin.Context, DB db.Env, userID string, sub *controlle
 		return
 	}
 
-	snap, err := DB.Restore("users/"+userHash+"/subject_reviews", subHash)
-
+	review, err := db.Get[models.SubjectReview](DB, "users/"+userHash+"/subject_reviews", subHash)
 	if err != nil { // user has not reviewed subject
-		if status.Code(err) == codes.NotFound {
-			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject review for %v and user %v: %s", model, userID, err.Error()))
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject review for %v and user %v: %s", model, userHash, err.Error()))
 			return
 		}
 
@@ -85,12 +76,7 @@ func GetSubjectReview(ctx *gin.Context, DB db.Env, userID string, sub *controlle
 		return
 	}
 
-	if err := snap.DataTo(&review); err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not bind subject review: %s", err.Error()))
-		return
-	}
-
-	private.GetSubjectReview(ctx, &review)
+	private.GetSubjectReview(ctx, review)
 }
 
 // UpdateSubjectReview is the model implementation for /server/controller/private/user.UpdateSubjectReview
@@ -104,7 +90,7 @@ func UpdateSubjectReview(ctx *gin.Context, DB db.Env, userID string, review *con
 		}
 
 		if err == db_utils.ErrNoPermission {
-			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to get review: %s", userID, err.Error()))
+			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("user %v has no permission to get review: %s", userHash, err.Error()))
 			return
 		}
 
@@ -113,58 +99,28 @@ func UpdateSubjectReview(ctx *gin.Context, DB db.Env, userID string, review *con
 	}
 
 	revRef := DB.Client.Doc("users/" + userHash + "/subject_reviews/" + model.Hash())
-	subRef := DB.Client.Doc("subjects/" + model.Hash())
-
-	err = DB.Client.RunTransaction(DB.Ctx, func(ctx context.Context, tx *firestore.Transaction) error {
-		rev, _ := tx.Get(revRef) // get existing review
+	counter := models.StatsCounter(DB, model.Hash())
 
-		if rev != nil && rev.Exists() { // user has already reviewed subject so we must remove it and propagate
-			if err != nil {
-				return err
-			}
+	err = db.RunTransaction(DB, "update subject review", func(ctx context.Context, tx *firestore.Transaction) error {
+		var stored *models.SubjectReview
 
-			categories, err := rev.DataAt("categories") // get existing review categories
-			if err != nil {
-				return err
-			}
-
-			for k, v := range categories.(map[string]interface{}) {
-				// decrement every category review which was true
-				if reflect.ValueOf(v).Kind() == reflect.Bool && v.(bool) {
-					path := fmt.Sprintf("stats.%s", k)
-					err = tx.Update(subRef, []firestore.Update{{Path: path, Value: firestore.Increment(-1)}})
-					if err != nil {
-						return err
-					}
-				}
-			}
-
-			// decrement number of total reviews
-			err = tx.Update(subRef, []firestore.Update{{Path: "stats.total", Value: firestore.Increment(-1)}})
-			if err != nil {
+		// user has already reviewed subject so we must remove it from the stats
+		if rev, err := tx.Get(revRef); err == nil {
+			stored = &models.SubjectReview{}
+			if err := rev.DataTo(stored); err != nil {
 				return err
 			}
+		} else if status.Code(err) != codes.NotFound {
+			return err
 		}
 
 		// add new review (overwrites if existing)
-		err := tx.Set(revRef, model)
-		if err != nil {
+		if err := tx.Set(revRef, model); err != nil {
 			return err
 		}
 
-		// update subject stats with new review
-		for k, v := range model.Review {
-			if reflect.ValueOf(v).Kind() == reflect.Bool && v.(bool) {
-				path := fmt.Sprintf("stats.%s", k)
-				err = tx.Update(subRef, []firestore.Update{{Path: path, Value: firestore.Increment(1)}})
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		// increment total of reviews
-		return tx.Update(subRef, []firestore.Update{{Path: "stats.total", Value: firestore.Increment(1)}})
+		// update subject stats, replacing the stored review by the new one
+		return counter.Increment(tx, models.ReviewStatsUpdates(stored, model))
 	})
 
 	if err != nil {
@@ -0,0 +1,56 @@
+package public
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/server/views/public"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCourseGraph gets the full prerequisite graph of a course
+func GetCourseGraph(ctx *gin.Context, DB db.Env, course *controllers.Course, opts *controllers.PrerequisiteGraphOptions) {
+	subjects, err := db_utils.GetCourseSubjects(DB, course.Code, course.Specialization)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subjects from course %v: %s", course, err.Error()))
+		return
+	} else if len(subjects) == 0 {
+		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subjects of course %v", course))
+		return
+	}
+
+	graph := models.NewPrerequisiteGraph(subjects)
+	public.GetPrerequisiteGraph(ctx, course.Code, course.Specialization, "", graph, opts.Format)
+}
+
+// GetSubjectGraph gets the transitive closure of a subject in its course prerequisite graph
+//
+// By default only the subject's predecessors are included, see controllers.PrerequisiteGraphOptions
+func GetSubjectGraph(ctx *gin.Context, DB db.Env, sub *controllers.Subject, opts *controllers.PrerequisiteGraphOptions) {
+	subjects, err := db_utils.GetCourseSubjects(DB, sub.CourseCode, sub.Specialization)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subjects from course of %v: %s", sub, err.Error()))
+		return
+	}
+
+	graph := models.NewPrerequisiteGraph(subjects)
+	if node, ok := graph.Nodes[sub.Code]; !ok || node.External {
+		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v in course graph", sub))
+		return
+	}
+
+	predecessors, successors := true, false
+	switch opts.Direction {
+	case "successors":
+		predecessors, successors = false, true
+	case "both":
+		successors = true
+	}
+
+	closure := graph.Closure(sub.Code, predecessors, successors)
+	public.GetPrerequisiteGraph(ctx, sub.CourseCode, sub.Specialization, sub.Code, closure, opts.Format)
+}
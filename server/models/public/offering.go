@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"cloud.google.com/go/firestore"
 	"github.com/Projeto-USPY/uspy-backend/db"
 	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
 	"github.com/Projeto-USPY/uspy-backend/server/views/public"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func GetOfferings(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
@@ -19,30 +18,19 @@ func GetOfferings(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
 	offerings := make([]*models.Offering, 0, 20)
 	IDs := make([]string, 0, 20)
 
-	snaps, err := DB.RestoreCollection("subjects/" + model.Hash() + "/offerings")
+	err := db.Each(DB, "subjects/"+model.Hash()+"/offerings", db.Query{}, func(ref *firestore.DocumentRef, off *models.Offering) error {
+		offerings = append(offerings, off)
+		IDs = append(IDs, ref.ID)
+		return nil
+	})
 
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find collection offerings: %s", err.Error()))
-			return
-		}
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch offerings: %s", err.Error()))
 		return
-	} else if len(snaps) == 0 {
+	} else if len(offerings) == 0 {
 		ctx.AbortWithStatus(http.StatusNotFound)
 		return
 	}
 
-	for _, s := range snaps {
-		var off models.Offering
-		if err := s.DataTo(&off); err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not bind offering: %s", err.Error()))
-			return
-		}
-
-		offerings = append(offerings, &off)
-		IDs = append(IDs, s.Ref.ID)
-	}
-
 	public.GetOfferings(ctx, IDs, offerings)
 }
@@ -0,0 +1,111 @@
+package public
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/search"
+	"github.com/Projeto-USPY/uspy-backend/server/views/public"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultProfessorSearchLimit is the number of results returned when the request does not specify a limit
+const defaultProfessorSearchLimit = 20
+
+// maxProfessorCandidates is the number of professors fetched from firestore before they are filtered by the whole query
+const maxProfessorCandidates = 200
+
+// GetProfessor returns the professor and every subject they taught
+func GetProfessor(ctx *gin.Context, DB db.Env, prof *controllers.Professor) {
+	model, err := db_utils.GetProfessor(DB, prof.Hash)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find professor %s: %s", prof.Hash, err.Error()))
+			return
+		}
+
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch professor: %s", err.Error()))
+		return
+	}
+
+	public.GetProfessor(ctx, model)
+}
+
+// SearchProfessors looks up professors by name
+//
+// Every word in the query must be the beginning of a word in the professor's name, ignoring accents
+func SearchProfessors(ctx *gin.Context, DB db.Env, query *controllers.ProfessorSearch) {
+	terms := search.Tokenize(query.Query)
+
+	// the longest term is used in the firestore query, since it matches the fewest professors
+	longest := ""
+	for _, t := range terms {
+		if len(t) > len(longest) {
+			longest = t
+		}
+	}
+
+	if len([]rune(longest)) < search.MinPrefix {
+		public.SearchProfessors(ctx, []*models.Professor{})
+		return
+	}
+
+	results := make([]*models.Professor, 0)
+	err := db.Each(DB, "professors", db.Query{
+		Where: []db.Filter{{Path: "tokens", Op: "array-contains", Value: longest}},
+		Limit: maxProfessorCandidates,
+	}, func(ref *firestore.DocumentRef, prof *models.Professor) error {
+		prof.Hash = ref.ID
+		if matchesName(prof.Name, terms) {
+			results = append(results, prof)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to search professors: %s", err.Error()))
+		return
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	limit := defaultProfessorSearchLimit
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	public.SearchProfessors(ctx, results)
+}
+
+// matchesName reports whether every term is the beginning of some word of the name
+func matchesName(name string, terms []string) bool {
+	words := search.Tokenize(name)
+	for _, t := range terms {
+		found := false
+		for _, w := range words {
+			if strings.HasPrefix(w, t) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
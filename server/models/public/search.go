@@ -0,0 +1,35 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/search"
+	"github.com/Projeto-USPY/uspy-backend/server/views/public"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSearchLimit is the number of results returned when the request does not specify a limit
+const defaultSearchLimit = 20
+
+// SearchSubjects looks up subjects in the search index by code, name and description
+func SearchSubjects(ctx *gin.Context, index *search.Index, query *controllers.SubjectSearch) {
+	if !index.Ready() {
+		ctx.AbortWithError(http.StatusServiceUnavailable, errors.New("search index has not been built yet"))
+		return
+	}
+
+	limit := defaultSearchLimit
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	filter := search.Filter{
+		Course:         query.CourseCode,
+		Specialization: query.Specialization,
+		Optional:       query.Optional,
+	}
+
+	public.SearchSubjects(ctx, index.Search(query.Query, filter, limit))
+}
@@ -2,113 +2,71 @@
 package public
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
 	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
 	"github.com/Projeto-USPY/uspy-backend/server/views/public"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/iterator"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 // GetAllSubjects gets all subjects from the database
 func GetAllSubjects(ctx *gin.Context, DB db.Env) {
-	snaps, err := DB.RestoreCollection("courses")
+	courses, meta, err := db_utils.GetCourses(DB)
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find collection courses: %s", err.Error()))
-			return
-		}
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch courses: %s", err.Error()))
 		return
 	}
 
-	courses := make([]models.Course, 0, 1000)
-	for _, s := range snaps {
-		var c models.Course
-		err = s.DataTo(&c)
-		courses = append(courses, c)
-		if err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch courses: %s", err.Error()))
-			return
-		}
-	}
-
-	public.GetAllSubjects(ctx, courses)
+	public.GetAllSubjects(ctx, courses, meta)
 }
 
 // Get gets a subject by its identifier: subject code, course code and course specialization code
 func Get(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
-	model := models.Subject{Code: sub.Code, CourseCode: sub.CourseCode, Specialization: sub.Specialization}
-	snap, err := DB.Restore("subjects", model.Hash())
+	model, err := db_utils.GetSubject(DB, models.NewSubjectFromController(sub).Hash())
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v: %s", model, err.Error()))
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v: %s", sub, err.Error()))
 			return
 		}
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subject: %s", err))
 		return
 	}
-	err = snap.DataTo(&model)
+
+	// review stats are the ones in the subject document plus its sharded counter
+	values, err := models.StatsCounter(DB, model.Hash()).Read(DB.Ctx)
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to bind subject to object: %s", err))
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to read subject stats: %s", err))
 		return
 	}
+	model.Stats.AddCounter(values)
 
-	public.Get(ctx, &model)
+	public.Get(ctx, model)
 }
 
 // GetRelations gets the subject's graph: their direct predecessors and successors
 func GetRelations(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
-	model := models.NewSubjectFromController(sub)
-	snap, err := DB.Restore("subjects", model.Hash())
-
+	model, err := db.Get[models.Subject](DB, "subjects", models.NewSubjectFromController(sub).Hash())
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v: %s", model, err.Error()))
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v: %s", sub, err.Error()))
 			return
 		}
 		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subject: %s", err.Error()))
 		return
 	}
 
-	if err := snap.DataTo(&model); err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not bind subject %v: %s", model, err.Error()))
-		return
-	}
-
 	getRelatedSubjects := func(model *models.Subject, strength bool) ([]models.Subject, error) {
 		requirement := models.Requirement{Subject: model.Code, Name: model.Name, Strong: strength}
-		iter := DB.Client.Collection("subjects").
-			Where("true_requirements", "array-contains", requirement).
-			Where("course", "==", sub.CourseCode).
-			Where("specialization", "==", sub.Specialization).
-			Documents(DB.Ctx)
-
-		results := make([]models.Subject, 0, 15)
-		for {
-			doc, err := iter.Next()
-			if err == iterator.Done {
-				break
-			}
-			if err != nil {
-				return []models.Subject{}, err
-			}
-			var result models.Subject
-			err = doc.DataTo(&result)
-			if err != nil {
-				return []models.Subject{}, err
-			}
-
-			results = append(results, result)
-		}
-		iter.Stop()
-
-		return results, nil
+		return db.List[models.Subject](DB, "subjects", db.Query{Where: []db.Filter{
+			{Path: "true_requirements", Op: "array-contains", Value: requirement},
+			{Path: "course", Op: "==", Value: sub.CourseCode},
+			{Path: "specialization", Op: "==", Value: sub.Specialization},
+		}})
 	}
 
 	strong, strongErr := getRelatedSubjects(model, true)
@@ -0,0 +1,103 @@
+package restricted
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
+	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/server/views/restricted"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCommentsLimit is the number of comments returned when the request does not specify a limit
+const defaultCommentsLimit = 20
+
+// getProfessor fetches the professor, aborting the request if it fails
+func getProfessor(ctx *gin.Context, DB db.Env, hash string) (*models.Professor, bool) {
+	prof, err := db_utils.GetProfessor(DB, hash)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find professor %s: %s", hash, err.Error()))
+			return nil, false
+		}
+
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch professor: %s", err.Error()))
+		return nil, false
+	}
+
+	return prof, true
+}
+
+// GetProfessorWithStats returns the professor along with the comment ratings of each subject they taught
+func GetProfessorWithStats(ctx *gin.Context, DB db.Env, prof *controllers.Professor) {
+	model, ok := getProfessor(ctx, DB, prof.Hash)
+	if !ok {
+		return
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(model.Offerings))
+	for _, off := range model.Offerings {
+		refs = append(refs, DB.Client.Doc(fmt.Sprintf("subjects/%s/offerings/%s", off.SubjectHash(), model.Hash)))
+	}
+
+	snaps, err := DB.Client.GetAll(DB.Ctx, refs)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch offerings: %s", err.Error()))
+		return
+	}
+
+	// offerings removed since the professors collection was built have no stats
+	stats := make([]models.OfferingStats, len(snaps))
+	for i, snap := range snaps {
+		if !snap.Exists() {
+			continue
+		}
+
+		var off models.Offering
+		if err := snap.DataTo(&off); err != nil {
+			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("could not bind offering: %s", err.Error()))
+			return
+		}
+
+		stats[i] = off.Stats
+	}
+
+	restricted.GetProfessorWithStats(ctx, model, stats)
+}
+
+// GetProfessorComments returns a page of the comments made about the professor in every subject they taught, most recent first
+func GetProfessorComments(ctx *gin.Context, DB db.Env, query *controllers.ProfessorComments) {
+	var after *models.CommentCursor
+	if query.Cursor != "" {
+		cursor, err := models.ParseCommentCursor(query.Cursor)
+		if err != nil {
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		after = &cursor
+	}
+
+	model, ok := getProfessor(ctx, DB, query.Hash)
+	if !ok {
+		return
+	}
+
+	limit := defaultCommentsLimit
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	comments, next, err := db_utils.GetProfessorComments(DB, model, after, limit)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch comments: %s", err.Error()))
+		return
+	}
+
+	restricted.GetProfessorComments(ctx, comments, next, limit)
+}
@@ -2,46 +2,89 @@
 package restricted
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
 	"github.com/Projeto-USPY/uspy-backend/entity/controllers"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
 	"github.com/Projeto-USPY/uspy-backend/server/views/restricted"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
+// getSubjectGrades fetches all grades from a given subject, aborting the request if it fails
+func getSubjectGrades(ctx *gin.Context, DB db.Env, model *models.Subject) ([]models.Grade, bool) {
+	// check subject existence
+	if _, err := db.Get[models.Subject](DB, "subjects", model.Hash()); errors.Is(err, db.ErrNotFound) {
+		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v: %s", model, err.Error()))
+		return nil, false
+	} else if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subject %v: %s", model, err.Error()))
+		return nil, false
+	}
+
+	grades, err := db.List[models.Grade](DB, fmt.Sprintf("subjects/%s/grades", model.Hash()), db.Query{})
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subject grades: %s", err.Error()))
+		return nil, false
+	}
+
+	return grades, true
+}
+
 // GetGrades returns all grades from a given subject
 func GetGrades(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
+	grades, ok := getSubjectGrades(ctx, DB, models.NewSubjectFromController(sub))
+	if !ok {
+		return
+	}
+
+	restricted.GetGrades(ctx, grades)
+}
+
+// GetGradesByProfessor returns the grades from a given subject grouped by the professor who taught it
+func GetGradesByProfessor(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
 	model := models.NewSubjectFromController(sub)
 
-	// check subject existence
-	if _, err := DB.Restore("subjects", model.Hash()); err != nil && status.Code(err) == codes.NotFound {
-		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("could not find subject %v: %s", model, err.Error()))
+	grades, ok := getSubjectGrades(ctx, DB, model)
+	if !ok {
 		return
 	}
 
-	snaps, err := DB.RestoreCollection(fmt.Sprintf("subjects/%s/grades", model.Hash()))
-
+	offerings, err := db_utils.GetSubjectOfferings(DB, model.Hash())
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch subject grades: %s", err.Error()))
+		ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to fetch offerings: %s", err.Error()))
 		return
 	}
 
-	grades := []models.Record{}
-	for _, s := range snaps {
-		g := models.Record{}
-		err := s.DataTo(&g)
-		if err != nil {
-			ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to bind subject %v: %s", model, err.Error()))
-			return
-		}
+	IDs := make([]string, 0, len(offerings))
+	offs := make([]*models.Offering, 0, len(offerings))
+	for ID, off := range offerings {
+		IDs = append(IDs, ID)
+		offs = append(offs, off)
+	}
+
+	restricted.GetGradesByProfessor(ctx, grades, IDs, offs)
+}
 
-		grades = append(grades, g)
+// GetGradesByYear returns the grades from a given subject grouped by year
+func GetGradesByYear(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
+	grades, ok := getSubjectGrades(ctx, DB, models.NewSubjectFromController(sub))
+	if !ok {
+		return
 	}
 
-	restricted.GetGrades(ctx, grades)
+	restricted.GetGradesByYear(ctx, grades)
+}
+
+// GetGradeTrend returns how the grades from a given subject changed over time
+func GetGradeTrend(ctx *gin.Context, DB db.Env, sub *controllers.Subject) {
+	grades, ok := getSubjectGrades(ctx, DB, models.NewSubjectFromController(sub))
+	if !ok {
+		return
+	}
+
+	restricted.GetGradeTrend(ctx, grades)
 }
@@ -0,0 +1,143 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CookieAuth is the security scheme of routes that require the access_token cookie set on login
+const CookieAuth = "cookieAuth"
+
+// Route documents one registered route
+//
+// Query, Body and Response hold zero values of the types bound or written by the route's handler, e.g. controllers.Subject{}
+type Route struct {
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+	Auth    bool // requires the access_token cookie
+
+	Query []interface{} // structs whose form tags are bound from the query string
+	Body  interface{}   // struct bound from the JSON body
+
+	Response    interface{} // nil if the response has no body
+	ContentType string      // of the response body, defaults to application/json
+
+	Error interface{} // body of error responses, nil if they have none
+}
+
+// Key identifies a route, it matches the method and path reported by gin
+func (r Route) Key() string {
+	return r.Method + " " + r.Path
+}
+
+// pathParam matches gin path parameters, such as :id
+var pathParam = regexp.MustCompile(`:([a-zA-Z_]+)`)
+
+// Build generates the document describing the given routes
+func Build(info Info, routes []Route) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				CookieAuth: {Type: "apiKey", In: "cookie", Name: "access_token"},
+			},
+		},
+	}
+
+	for _, r := range routes {
+		op := &Operation{
+			Summary:   r.Summary,
+			Responses: make(map[string]*Response),
+		}
+
+		if r.Tag != "" {
+			op.Tags = []string{r.Tag}
+		}
+
+		for _, q := range r.Query {
+			op.Parameters = mergeParameters(op.Parameters, g.parameters(reflect.TypeOf(q)))
+		}
+
+		for _, name := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+			op.Parameters = append(op.Parameters, &Parameter{Name: name[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+
+		if r.Body != nil {
+			g.request = true
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: g.schema(reflect.TypeOf(r.Body))}},
+			}
+			g.request = false
+		}
+
+		ok := &Response{Description: http.StatusText(http.StatusOK)}
+		if r.Response != nil {
+			contentType := r.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+
+			media := &MediaType{}
+			if contentType == "application/json" {
+				media.Schema = g.schema(reflect.TypeOf(r.Response))
+			} else {
+				media.Schema = &Schema{Type: "string", Format: "binary"}
+			}
+
+			ok.Content = map[string]*MediaType{contentType: media}
+		}
+		op.Responses[strconv.Itoa(http.StatusOK)] = ok
+
+		if len(op.Parameters) > 0 || r.Body != nil {
+			op.Responses[strconv.Itoa(http.StatusBadRequest)] = &Response{Description: http.StatusText(http.StatusBadRequest)}
+		}
+
+		if r.Auth {
+			op.Security = []map[string][]string{{CookieAuth: {}}}
+			op.Responses[strconv.Itoa(http.StatusUnauthorized)] = &Response{Description: http.StatusText(http.StatusUnauthorized)}
+		}
+
+		if r.Error != nil {
+			op.Responses["default"] = &Response{Description: "Error"}
+			for _, res := range op.Responses {
+				if res != ok {
+					res.Content = map[string]*MediaType{"application/json": {Schema: g.schema(reflect.TypeOf(r.Error))}}
+				}
+			}
+		}
+
+		path := pathParam.ReplaceAllString(r.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		doc.Paths[path][strings.ToLower(r.Method)] = op
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+// mergeParameters appends the parameters that are not listed yet, binders of nested routes share fields
+func mergeParameters(params, more []*Parameter) []*Parameter {
+	for _, p := range more {
+		exists := false
+		for _, other := range params {
+			exists = exists || other.Name == p.Name
+		}
+
+		if !exists {
+			params = append(params, p)
+		}
+	}
+
+	return params
+}
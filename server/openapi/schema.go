@@ -0,0 +1,242 @@
+package openapi
+
+import (
+	"encoding"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator converts Go types to schemas, named structs are added to the components and referenced
+type generator struct {
+	schemas map[string]*Schema
+
+	// request is set while describing request bodies, whose required fields come from binding tags instead of json tags
+	request bool
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[string]*Schema)}
+}
+
+// componentName is the package qualified type name, e.g. views.Subject, since views and controllers reuse names
+func componentName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.String && reflect.PtrTo(t).Implements(textMarshalerType):
+		// e.g. uuid.UUID
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+
+		name := componentName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = nil // placeholder, so recursive types terminate
+			g.schemas[name] = g.object(t)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interfaces can hold anything
+		return &Schema{}
+	}
+}
+
+// object builds the schema of a struct from its json tags
+//
+// Fields bound from the query string (with a form tag but no json tag) are left out, embedded structs are flattened
+func (g *generator) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(obj, t)
+	return obj
+}
+
+func (g *generator) addFields(obj *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("json")
+		name, opts := splitTag(tag)
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(obj, ft)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if !hasTag {
+			if _, bound := f.Tag.Lookup("form"); bound {
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		rules := parseBinding(f.Tag.Get("binding"))
+		obj.Properties[name] = constrain(g.schema(f.Type), rules)
+		if g.request && rules.required || !g.request && !strings.Contains(opts, "omitempty") {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+}
+
+// parameters lists the query parameters bound from the form tags of a struct
+func (g *generator) parameters(t reflect.Type) []*Parameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	params := make([]*Parameter, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _ := splitTag(f.Tag.Get("form"))
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			params = append(params, g.parameters(f.Type)...)
+			continue
+		}
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		rules := parseBinding(f.Tag.Get("binding"))
+		params = append(params, &Parameter{
+			Name:     name,
+			In:       "query",
+			Required: rules.required,
+			Schema:   constrain(g.schema(f.Type), rules),
+		})
+	}
+
+	return params
+}
+
+func splitTag(tag string) (name, opts string) {
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+
+	return tag, ""
+}
+
+// bindingRules are the validator rules that can be described in a schema, custom validators are ignored
+type bindingRules struct {
+	required bool
+	min, max *float64
+	oneOf    []string
+	format   string
+	pattern  string
+}
+
+func parseBinding(tag string) bindingRules {
+	var rules bindingRules
+	number := func(value string) *float64 {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return &n
+		}
+
+		return nil
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, value := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			key, value = rule[:i], rule[i+1:]
+		}
+
+		switch key {
+		case "required":
+			rules.required = true
+		case "min", "gte":
+			rules.min = number(value)
+		case "max", "lte":
+			rules.max = number(value)
+		case "len":
+			rules.min, rules.max = number(value), number(value)
+		case "oneof":
+			rules.oneOf = strings.Fields(value)
+		case "email", "uuid":
+			rules.format = key
+		case "alphanum":
+			rules.pattern = "^[a-zA-Z0-9]+$"
+		case "numeric":
+			rules.pattern = "^[0-9]+$"
+		}
+	}
+
+	return rules
+}
+
+// constrain adds the binding rules to a primitive schema, references are returned as is
+func constrain(s *Schema, rules bindingRules) *Schema {
+	switch s.Type {
+	case "string":
+		s.Enum = rules.oneOf
+		if rules.format != "" {
+			s.Format = rules.format
+		}
+		s.Pattern = rules.pattern
+		if rules.min != nil {
+			n := int(*rules.min)
+			s.MinLength = &n
+		}
+		if rules.max != nil {
+			n := int(*rules.max)
+			s.MaxLength = &n
+		}
+	case "integer", "number":
+		s.Minimum, s.Maximum = rules.min, rules.max
+	}
+
+	return s
+}
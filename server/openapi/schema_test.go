@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testQuery struct {
+	Code  string `form:"code" binding:"required,alphanum"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Sort  string `form:"sort" binding:"omitempty,oneof=asc desc"`
+}
+
+type testBody struct {
+	testQuery
+	Email    string `json:"email" binding:"required,email"`
+	Token    string `json:"token" binding:"required,len=64"`
+	Remember bool   `json:"remember"`
+}
+
+type testPrivacy struct {
+	Suppressed bool `json:"suppressed,omitempty"`
+}
+
+type testView struct {
+	Name      string            `json:"name"`
+	Tags      []string          `json:"tags"`
+	Histogram map[string]int    `json:"histogram"`
+	Timestamp time.Time         `json:"timestamp"`
+	Children  []*testView       `json:"children,omitempty"`
+	Extra     interface{}       `json:"extra,omitempty"`
+	Hidden    string            `json:"-"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	testPrivacy
+}
+
+func TestParameters(t *testing.T) {
+	params := newGenerator().parameters(reflect.TypeOf(testQuery{}))
+	require.Len(t, params, 3)
+
+	assert.Equal(t, "code", params[0].Name)
+	assert.True(t, params[0].Required)
+	assert.Equal(t, "^[a-zA-Z0-9]+$", params[0].Schema.Pattern)
+
+	assert.False(t, params[1].Required)
+	assert.Equal(t, 1.0, *params[1].Schema.Minimum)
+	assert.Equal(t, 100.0, *params[1].Schema.Maximum)
+
+	assert.Equal(t, []string{"asc", "desc"}, params[2].Schema.Enum)
+}
+
+func TestSchema(t *testing.T) {
+	t.Run("request body", func(t *testing.T) {
+		g := newGenerator()
+		g.request = true
+		ref := g.schema(reflect.TypeOf(testBody{}))
+
+		body := g.schemas[componentName(reflect.TypeOf(testBody{}))]
+		require.NotNil(t, body)
+		assert.Equal(t, "#/components/schemas/openapi.testBody", ref.Ref)
+		assert.NotContains(t, body.Properties, "code", "query fields are not part of the body")
+		assert.Equal(t, "email", body.Properties["email"].Format)
+		assert.Equal(t, 64, *body.Properties["token"].MinLength)
+		assert.Equal(t, 64, *body.Properties["token"].MaxLength)
+		assert.ElementsMatch(t, []string{"email", "token"}, body.Required)
+	})
+
+	t.Run("response", func(t *testing.T) {
+		g := newGenerator()
+		g.schema(reflect.TypeOf([]testView{}))
+
+		view := g.schemas["openapi.testView"]
+		require.NotNil(t, view)
+		assert.Equal(t, "array", view.Properties["tags"].Type)
+		assert.Equal(t, "integer", view.Properties["histogram"].AdditionalProperties.Type)
+		assert.Equal(t, "date-time", view.Properties["timestamp"].Format)
+		assert.Equal(t, "#/components/schemas/openapi.testView", view.Properties["children"].Items.Ref, "recursive types are referenced")
+		assert.Contains(t, view.Properties, "suppressed", "embedded structs are flattened")
+		assert.NotContains(t, view.Properties, "Hidden")
+		assert.ElementsMatch(t, []string{"name", "tags", "histogram", "timestamp"}, view.Required)
+	})
+}
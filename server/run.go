@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Projeto-USPY/uspy-backend/config"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+)
+
+// workers tracks the background goroutines started by SetupRouter, they stop when the context of their db.Env is done
+var workers sync.WaitGroup
+
+// goWorker runs f in a background goroutine that shutdown waits for
+func goWorker(f func()) {
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		f()
+	}()
+}
+
+// WaitWorkers waits for the background workers to stop, at most until ctx is done
+func WaitWorkers(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run serves handler at addr until ctx is done, then drains it
+//
+// Draining fails the readiness probe, stops accepting connections and waits up to config.Env.ShutdownTimeout for
+// in-flight requests to finish
+func Run(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  config.Env.ReadTimeout,
+		WriteTimeout: config.Env.WriteTimeout,
+		IdleTimeout:  config.Env.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	logger.Info("server listening", logger.Fields{"addr": addr})
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	atomic.StoreInt32(&draining, 1)
+	logger.Info("shutting down, draining in-flight requests", logger.Fields{"timeout": config.Env.ShutdownTimeout.String()})
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Env.ShutdownTimeout)
+	defer cancel()
+
+	err := srv.Shutdown(shutdownCtx)
+	if serveErr := <-serveErr; !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// whatever is left of the timeout is given to the workers
+	if err := WaitWorkers(shutdownCtx); err != nil {
+		return errors.New("background workers did not stop in time")
+	}
+
+	return nil
+}
@@ -2,16 +2,26 @@
 package server
 
 import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+
 	"github.com/Projeto-USPY/uspy-backend/config"
 	"github.com/Projeto-USPY/uspy-backend/db"
+	db_utils "github.com/Projeto-USPY/uspy-backend/db/utils"
 	"github.com/Projeto-USPY/uspy-backend/entity"
 	"github.com/Projeto-USPY/uspy-backend/entity/validation"
+	"github.com/Projeto-USPY/uspy-backend/logger"
+	"github.com/Projeto-USPY/uspy-backend/metrics"
+	"github.com/Projeto-USPY/uspy-backend/search"
 	"github.com/Projeto-USPY/uspy-backend/server/controllers/account"
 	"github.com/Projeto-USPY/uspy-backend/server/controllers/private"
 	"github.com/Projeto-USPY/uspy-backend/server/controllers/public"
 	"github.com/Projeto-USPY/uspy-backend/server/controllers/restricted"
 	"github.com/Projeto-USPY/uspy-backend/server/middleware"
+	"github.com/Projeto-USPY/uspy-backend/tracing"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func setupAccount(DB db.Env, accountGroup *gin.RouterGroup) {
@@ -32,20 +42,31 @@ func setupAccount(DB db.Env, accountGroup *gin.RouterGroup) {
 	}
 }
 
-func setupPublic(DB db.Env, apiGroup *gin.RouterGroup) {
+func setupPublic(DB db.Env, index *search.Index, apiGroup *gin.RouterGroup) {
 	apiGroup.GET("/subject/all", public.GetSubjects(DB))
+	apiGroup.GET("/subject/search", public.SearchSubjects(index))
+	apiGroup.GET("/course/graph", public.GetCourseGraph(DB))
+	apiGroup.GET("/professor", public.GetProfessor(DB))
+	apiGroup.GET("/professor/search", public.SearchProfessors(DB))
 	subjectAPI := apiGroup.Group("/subject", entity.SubjectBinder)
 	{
 		subjectAPI.GET("", public.GetSubjectByCode(DB))
 		subjectAPI.GET("/relations", public.GetRelations(DB))
+		subjectAPI.GET("/graph", public.GetSubjectGraph(DB))
 		subjectAPI.GET("/offerings", public.GetOfferings(DB))
 	}
 }
 
 func setupRestricted(DB db.Env, restrictedGroup *gin.RouterGroup) {
+	restrictedGroup.GET("/professor", restricted.GetProfessorWithStats(DB))
+	restrictedGroup.GET("/professor/comments", restricted.GetProfessorComments(DB))
+
 	subjectAPI := restrictedGroup.Group("/subject", entity.SubjectBinder)
 	{
 		subjectAPI.GET("/grades", restricted.GetGrades(DB))
+		subjectAPI.GET("/grades/professors", restricted.GetGradesByProfessor(DB))
+		subjectAPI.GET("/grades/years", restricted.GetGradesByYear(DB))
+		subjectAPI.GET("/grades/trend", restricted.GetGradeTrend(DB))
 		subjectAPI.GET("/offerings", restricted.GetOfferingsWithStats(DB))
 
 		offeringsAPI := subjectAPI.Group("/offerings", entity.OfferingBinder)
@@ -56,6 +77,9 @@ func setupRestricted(DB db.Env, restrictedGroup *gin.RouterGroup) {
 }
 
 func setupPrivate(DB db.Env, privateGroup *gin.RouterGroup) {
+	privateGroup.GET("/course/plan", private.GetCurriculumPlan(DB))
+	privateGroup.GET("/course/progress", private.GetDegreeProgress(DB))
+
 	subjectAPI := privateGroup.Group("/subject", entity.SubjectBinder)
 	{
 		subjectAPI.GET("/grade", private.GetSubjectGrade(DB))
@@ -77,15 +101,49 @@ func setupPrivate(DB db.Env, privateGroup *gin.RouterGroup) {
 	}
 }
 
+// setupRoutes registers every API route under root, each one must be documented in routeDocs
+func setupRoutes(DB db.Env, index *search.Index, root *gin.RouterGroup) {
+	// Login, Logout, Sign-in and other account related operations
+	setupAccount(DB, root.Group("/account", middleware.CacheControl(middleware.NoStore)))
+
+	// Public endpoints: available for all users, including guests
+	setupPublic(DB, index, root.Group("/api", middleware.CacheControl(middleware.PublicCache)))
+
+	// Restricted endpoints: available only for registered users
+	setupRestricted(DB, root.Group("/api/restricted", middleware.CacheControl(middleware.PrivateCache), middleware.JWT()))
+
+	// Private endpoints: every endpoint related to operations that the user utilizes their own data
+	setupPrivate(DB, root.Group("/private", middleware.CacheControl(middleware.PrivateCache), middleware.JWT()))
+}
+
+// SetupRouter registers every route and starts the background workers, which stop when DB.Ctx is done
 func SetupRouter(DB db.Env) (*gin.Engine, error) {
-	r := gin.Default() // Create web-server object
+	r := gin.New() // Create web-server object, requests are logged by middleware.Logger
 
 	err := validation.SetupValidators()
 	if err != nil {
 		return nil, err
 	}
 
-	r.Use(gin.Recovery(), middleware.DefineDomain(), middleware.DumpErrors())
+	// cache catalog data, subject changes are picked up by the search index watcher
+	DB.Cache = db.NewCache(DB)
+	if DB.Cache != nil {
+		goWorker(func() { db_utils.WatchCourses(DB.Ctx, DB) })
+	}
+
+	// build subject search index and keep it updated
+	index := search.NewIndex()
+	if err := index.Load(DB); err != nil {
+		logger.Warn("could not build search index, waiting for subject changes", logger.Fields{"error": err})
+	}
+	index.OnChange = func(ids []string) { db_utils.InvalidateSubjects(DB.Ctx, DB.Cache, ids) }
+	goWorker(func() { index.Watch(DB.Ctx, DB) })
+
+	r.Use(otelgin.Middleware(tracing.ServiceName), middleware.RequestID(), middleware.Logger(), middleware.Metrics(), middleware.Recovery(), middleware.DefineDomain(), middleware.Timeout(config.Env.RequestTimeout), middleware.Compress(), middleware.ETag(), middleware.Locale(), middleware.ErrorEnvelope())
+
+	// probes are registered before the rate limiter, so they are never throttled
+	r.GET("/healthz", Healthz)
+	r.GET("/readyz", Readyz(readinessChecks(DB)))
 
 	if config.Env.IsLocal() {
 		r.Use(middleware.AllowAnyOrigin())
@@ -96,17 +154,28 @@ func SetupRouter(DB db.Env) (*gin.Engine, error) {
 		r.Use(middleware.AllowUSPYOrigin())
 	}
 
-	// Login, Logout, Sign-in and other account related operations
-	setupAccount(DB, r.Group("/account"))
+	// cache metrics and other runtime counters
+	if config.Env.IsLocal() || config.Env.IsDev() {
+		r.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
 
-	// Public endpoints: available for all users, including guests
-	setupPublic(DB, r.Group("/api"))
+	// Prometheus metrics, scrapers must send the token if one is configured
+	r.GET("/metrics", middleware.BearerToken(config.Env.MetricsToken), gin.WrapH(metrics.Handler()))
 
-	// Restricted endpoints: available only for registered users
-	setupRestricted(DB, r.Group("/api/restricted", middleware.JWT()))
+	// API description, see docs.go
+	doc, err := json.Marshal(apiDocument())
+	if err != nil {
+		return nil, err
+	}
+	r.GET("/openapi.json", func(ctx *gin.Context) { ctx.Data(http.StatusOK, "application/json; charset=utf-8", doc) })
+	if config.Env.SwaggerUI {
+		r.GET("/docs", func(ctx *gin.Context) { ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUI)) })
+	}
 
-	// Private endpoints: every endpoint related to operations that the user utilizes their own data
-	setupPrivate(DB, r.Group("/private", middleware.JWT()))
+	setupRoutes(DB, index, &r.RouterGroup)
+
+	// the same routes, whose errors are always written as views.ErrorEnvelope
+	setupRoutes(DB, index, r.Group(middleware.V2Prefix))
 
 	return r, nil
 }
@@ -0,0 +1,13 @@
+package private
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/gin-gonic/gin"
+)
+
+func GetCurriculumPlan(ctx *gin.Context, course, specialization string, plan *models.CurriculumPlan) {
+	ctx.JSON(http.StatusOK, views.NewCurriculumPlanFromModel(course, specialization, plan))
+}
@@ -0,0 +1,13 @@
+package private
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/gin-gonic/gin"
+)
+
+func GetDegreeProgress(ctx *gin.Context, course, specialization string, progress *models.DegreeProgress) {
+	ctx.JSON(http.StatusOK, views.NewDegreeProgressFromModel(course, specialization, progress))
+}
@@ -0,0 +1,20 @@
+package public
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/gin-gonic/gin"
+)
+
+func GetPrerequisiteGraph(ctx *gin.Context, course, specialization, root string, graph *models.PrerequisiteGraph, format string) {
+	result := views.NewPrerequisiteGraphFromModel(course, specialization, root, graph)
+
+	if format == "dot" {
+		ctx.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(result.DOT()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
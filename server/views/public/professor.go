@@ -0,0 +1,22 @@
+package public
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/gin-gonic/gin"
+)
+
+func GetProfessor(ctx *gin.Context, prof *models.Professor) {
+	ctx.JSON(http.StatusOK, views.NewPartialProfessorFromModel(prof))
+}
+
+func SearchProfessors(ctx *gin.Context, professors []*models.Professor) {
+	results := make([]*views.ProfessorSearchResult, 0, len(professors))
+	for _, p := range professors {
+		results = append(results, views.NewProfessorSearchResultFromModel(p))
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
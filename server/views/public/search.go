@@ -0,0 +1,18 @@
+package public
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/Projeto-USPY/uspy-backend/search"
+	"github.com/gin-gonic/gin"
+)
+
+func SearchSubjects(ctx *gin.Context, results []search.Result) {
+	response := make([]*views.SubjectSearchResult, 0, len(results))
+	for _, r := range results {
+		response = append(response, views.NewSubjectSearchResultFromModel(r.Subject, r.Score))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -3,12 +3,21 @@ package public
 import (
 	"net/http"
 
+	"github.com/Projeto-USPY/uspy-backend/cache"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
 	"github.com/Projeto-USPY/uspy-backend/entity/views"
 	"github.com/gin-gonic/gin"
 )
 
-func GetAllSubjects(ctx *gin.Context, courses []models.Course) {
+// GetAllSubjects writes every course, or 304 Not Modified if the client's copy is still valid
+func GetAllSubjects(ctx *gin.Context, courses []models.Course, meta cache.Meta) {
+	ctx.Header("ETag", meta.ETag)
+	ctx.Header("Last-Modified", meta.HTTPTime())
+	if meta.NotModified(ctx.GetHeader("If-None-Match"), ctx.GetHeader("If-Modified-Since")) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
 	viewCourses := make([]views.Course, 0, 1000)
 	for i := range courses {
 		viewCourses = append(viewCourses, *views.NewCourseFromModel(&courses[i]))
@@ -0,0 +1,28 @@
+package restricted
+
+import (
+	"net/http"
+
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
+	"github.com/gin-gonic/gin"
+)
+
+func GetProfessorWithStats(ctx *gin.Context, prof *models.Professor, stats []models.OfferingStats) {
+	ctx.JSON(http.StatusOK, views.NewProfessorFromModel(prof, stats))
+}
+
+// GetProfessorComments returns a page of comments, with the cursor of the next one if there is one
+func GetProfessorComments(ctx *gin.Context, comments []models.ProfessorComment, next *models.CommentCursor, limit int) {
+	results := make([]*views.ProfessorComment, 0, len(comments))
+	for _, c := range comments {
+		results = append(results, views.NewProfessorCommentFromModel(c.Offering, c.Comment))
+	}
+
+	page := views.ProfessorComments{Comments: results, Limit: limit}
+	if next != nil {
+		page.Next = next.String()
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
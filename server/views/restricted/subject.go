@@ -1,46 +1,87 @@
 package restricted
 
 import (
-	"fmt"
 	"net/http"
-	"strconv"
+	"sort"
 
-	"github.com/Projeto-USPY/uspy-backend/config"
 	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/entity/views"
 	"github.com/gin-gonic/gin"
 )
 
-func GetGrades(ctx *gin.Context, grades []models.Record) {
-	buckets := make(map[string]int)
+func GetGrades(ctx *gin.Context, grades []models.Grade) {
+	ctx.JSON(http.StatusOK, views.NewProtectedGradeDistributionFromModel(models.NewGradeDistribution(grades)))
+}
+
+// GetGradesByProfessor returns the distribution of grades given in each offering
+//
+// Grades whose offering is unknown are left out
+func GetGradesByProfessor(ctx *gin.Context, grades []models.Grade, IDs []string, offerings []*models.Offering) {
+	groups := models.GradesByOffering(grades)
+
+	dists := make([]models.GradeDistribution, 0, len(offerings))
+	for _, ID := range IDs {
+		dists = append(dists, models.NewGradeDistribution(groups[ID]))
+	}
 
-	for _, g := range grades {
-		buckets[fmt.Sprintf("%.1f", g.Grade)]++
+	results := make([]views.ProfessorGrades, 0, len(offerings))
+	for i, dist := range views.NewProtectedGradeDistributionsFromModels(dists) {
+		results = append(results, views.ProfessorGrades{
+			ProfessorName:     offerings[i].Professor,
+			ProfessorCode:     IDs[i],
+			GradeDistribution: dist,
+		})
 	}
 
-	avg, approval := 0.0, 0.0
-	cnt := 0
+	sort.SliceStable(results, func(i, j int) bool { return results[i].ProfessorName < results[j].ProfessorName })
+	ctx.JSON(http.StatusOK, results)
+}
+
+// GetGradesByYear returns the distribution of grades given in each year
+func GetGradesByYear(ctx *gin.Context, grades []models.Grade) {
+	groups := models.GradesByYear(grades)
 
-	// calculate average grade and approval rate
-	for k, v := range buckets {
-		f, _ := strconv.ParseFloat(k, 64)
-		avg += f * float64(v)
+	years := make([]int, 0, len(groups))
+	for year := range groups {
+		years = append(years, year)
+	}
+	sort.Ints(years)
 
-		if f >= 5.0 {
-			approval += float64(v)
-		}
+	dists := make([]models.GradeDistribution, 0, len(years))
+	for _, year := range years {
+		dists = append(dists, models.NewGradeDistribution(groups[year]))
+	}
 
-		cnt += v
+	results := make([]views.YearGrades, 0, len(years))
+	for i, dist := range views.NewProtectedGradeDistributionsFromModels(dists) {
+		results = append(results, views.YearGrades{
+			Year:              years[i],
+			GradeDistribution: dist,
+		})
 	}
 
-	if cnt > 0 {
-		avg /= float64(cnt)
-		approval /= float64(cnt)
+	ctx.JSON(http.StatusOK, results)
+}
+
+// GetGradeTrend returns the average grade and approval rate of each semester, in chronological order
+func GetGradeTrend(ctx *gin.Context, grades []models.Grade) {
+	groups, periods := models.GradesByPeriod(grades)
+
+	dists := make([]models.GradeDistribution, 0, len(periods))
+	for _, p := range periods {
+		dists = append(dists, models.NewGradeDistribution(groups[p]))
+	}
 
-		if config.Env.Mode == "prod" && cnt <= 10 { // do not return grades if there are too few grades
-			ctx.JSON(http.StatusOK, gin.H{"grades": map[string]int{}, "average": 0.0, "approval": 0.0})
-			return
-		}
+	results := make([]views.PeriodGrades, 0, len(periods))
+	for i, dist := range views.NewProtectedGradeDistributionsFromModels(dists) {
+		results = append(results, views.PeriodGrades{
+			Year:     periods[i].Year,
+			Semester: periods[i].Semester,
+			Average:  dist.Average,
+			Approval: dist.Approval,
+			Privacy:  dist.Privacy,
+		})
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"grades": buckets, "average": avg, "approval": approval})
+	ctx.JSON(http.StatusOK, results)
 }
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/Projeto-USPY/uspy-backend/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// collectionsKey lists the collections a Firestore operation touched
+const collectionsKey = attribute.Key("firestore.collections")
+
+// FirestoreDialOptions trace each operation of a Firestore client as a child of the request that issued it
+//
+// Like metrics.FirestoreDialOptions, they have no effect on emulator clients
+func FirestoreDialOptions() []grpc.DialOption {
+	unary, stream := otelgrpc.UnaryClientInterceptor(), otelgrpc.StreamClientInterceptor()
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unary, annotateUnary),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			// listeners stay open for as long as the server runs, a span would never end
+			if desc.ClientStreams {
+				return streamer(ctx, desc, cc, method, opts...)
+			}
+
+			return stream(ctx, desc, cc, method, annotateStream(streamer), opts...)
+		}),
+	}
+}
+
+func annotateUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	trace.SpanFromContext(ctx).SetAttributes(collectionsKey.StringSlice(metrics.FirestoreCollections(req)))
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// annotateStream wraps the streams created by streamer, whose context carries the operation's span
+func annotateStream(streamer grpc.Streamer) grpc.Streamer {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &annotatedStream{ClientStream: s, span: trace.SpanFromContext(ctx)}, nil
+	}
+}
+
+// annotatedStream adds the collections of the request sent on a stream to its span
+type annotatedStream struct {
+	grpc.ClientStream
+	span trace.Span
+}
+
+func (s *annotatedStream) SendMsg(m interface{}) error {
+	s.span.SetAttributes(collectionsKey.StringSlice(metrics.FirestoreCollections(m)))
+	return s.ClientStream.SendMsg(m)
+}
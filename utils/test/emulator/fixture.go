@@ -0,0 +1,296 @@
+package emulator
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Projeto-USPY/uspy-backend/db"
+	"github.com/Projeto-USPY/uspy-backend/entity/models"
+	"github.com/Projeto-USPY/uspy-backend/iddigital"
+	"github.com/Projeto-USPY/uspy-backend/server/models/account"
+	"github.com/Projeto-USPY/uspy-backend/utils"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultFixture is the fixture set loaded by Setup and MustGet
+const DefaultFixture = "default"
+
+//go:embed fixtures/*.yaml
+var fixtureFiles embed.FS
+
+// fixtureName restricts fixture names to characters that are valid in a project ID, see ProjectID
+var fixtureName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// Fixture is a set of documents loaded into the emulator, it is read from fixtures/{name}.yaml
+type Fixture struct {
+	Subjects []FixtureSubject `yaml:"subjects"`
+	Courses  []FixtureCourse  `yaml:"courses"`
+	Users    []FixtureUser    `yaml:"users"`
+}
+
+type FixtureRequirement struct {
+	Code   string `yaml:"code"`
+	Name   string `yaml:"name"`
+	Strong bool   `yaml:"strong"`
+}
+
+type FixtureSubject struct {
+	Code             string                          `yaml:"code"`
+	Course           string                          `yaml:"course"`
+	Specialization   string                          `yaml:"specialization"`
+	Name             string                          `yaml:"name"`
+	Description      string                          `yaml:"description"`
+	Semester         int                             `yaml:"semester"`
+	ClassCredits     int                             `yaml:"class_credits"`
+	AssignCredits    int                             `yaml:"assign_credits"`
+	TotalHours       string                          `yaml:"total_hours"`
+	Optional         bool                            `yaml:"optional"`
+	Requirements     map[string][]FixtureRequirement `yaml:"requirements"` // requirement group -> requirements
+	TrueRequirements []FixtureRequirement            `yaml:"true_requirements"`
+
+	Offerings []FixtureOffering `yaml:"offerings"`
+}
+
+type FixtureOffering struct {
+	Professor string   `yaml:"professor"`
+	Code      string   `yaml:"code"` // professor's codpes
+	Years     []string `yaml:"years"`
+
+	Comments []FixtureComment `yaml:"comments"`
+}
+
+type FixtureComment struct {
+	User   string        `yaml:"user"` // author's NUSP
+	Rating int           `yaml:"rating"`
+	Body   string        `yaml:"body"`
+	Votes  []FixtureVote `yaml:"votes"`
+}
+
+type FixtureVote struct {
+	User   string `yaml:"user"` // voter's NUSP
+	Upvote bool   `yaml:"upvote"`
+}
+
+type FixtureCourse struct {
+	Name           string            `yaml:"name"`
+	Code           string            `yaml:"code"`
+	Specialization string            `yaml:"specialization"`
+	Subjects       map[string]string `yaml:"subjects"` // subject code -> subject name
+}
+
+type FixtureUser struct {
+	ID             string          `yaml:"id"` // NUSP, also used to log in
+	Name           string          `yaml:"name"`
+	Email          string          `yaml:"email"`
+	Password       string          `yaml:"password"`
+	Verified       bool            `yaml:"verified"`
+	Banned         bool            `yaml:"banned"`
+	Course         string          `yaml:"course"`
+	Specialization string          `yaml:"specialization"`
+	Transcript     []FixtureRecord `yaml:"transcript"`
+}
+
+type FixtureRecord struct {
+	Subject        string  `yaml:"subject"`
+	Course         string  `yaml:"course"`
+	Specialization string  `yaml:"specialization"`
+	Year           int     `yaml:"year"`
+	Semester       int     `yaml:"semester"`
+	Grade          float64 `yaml:"grade"`
+	Frequency      int     `yaml:"frequency"`
+	Status         string  `yaml:"status"`
+}
+
+// LoadFixture reads the fixture set with the given name
+func LoadFixture(name string) (*Fixture, error) {
+	if !fixtureName.MatchString(name) {
+		return nil, fmt.Errorf("invalid fixture name %q", name)
+	}
+
+	raw, err := fixtureFiles.ReadFile(path.Join("fixtures", name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("could not read fixture %s: %s", name, err.Error())
+	}
+
+	var fixture Fixture
+	if err := yaml.UnmarshalStrict(raw, &fixture); err != nil {
+		return nil, fmt.Errorf("could not parse fixture %s: %s", name, err.Error())
+	}
+
+	return &fixture, nil
+}
+
+// ProjectID returns the emulator project a fixture set is loaded into
+//
+// Each fixture set has its own project, so sets never see each other's documents
+func ProjectID(name string) string {
+	if name == DefaultFixture {
+		return "test"
+	}
+
+	return "test-" + name
+}
+
+func requirementsFromFixture(reqs []FixtureRequirement) []models.Requirement {
+	if reqs == nil {
+		return nil
+	}
+
+	converted := make([]models.Requirement, 0, len(reqs))
+	for _, r := range reqs {
+		converted = append(converted, models.Requirement{Subject: r.Code, Name: r.Name, Strong: r.Strong})
+	}
+
+	return converted
+}
+
+func (s FixtureSubject) model() models.Subject {
+	sub := models.Subject{
+		Code:             s.Code,
+		CourseCode:       s.Course,
+		Specialization:   s.Specialization,
+		Name:             s.Name,
+		Description:      s.Description,
+		Semester:         s.Semester,
+		ClassCredits:     s.ClassCredits,
+		AssignCredits:    s.AssignCredits,
+		TotalHours:       s.TotalHours,
+		TrueRequirements: requirementsFromFixture(s.TrueRequirements),
+		Optional:         s.Optional,
+		Stats:            models.NewSubjectStats(),
+	}
+
+	if s.Requirements != nil {
+		sub.Requirements = make(map[string][]models.Requirement, len(s.Requirements))
+		for group, reqs := range s.Requirements {
+			sub.Requirements[group] = requirementsFromFixture(reqs)
+		}
+	}
+
+	return sub
+}
+
+func (r FixtureRecord) model() models.Record {
+	return models.Record{
+		Subject:        r.Subject,
+		Course:         r.Course,
+		Specialization: r.Specialization,
+		Year:           r.Year,
+		Semester:       r.Semester,
+		Grade:          r.Grade,
+		Frequency:      r.Frequency,
+		Status:         r.Status,
+	}
+}
+
+// Load writes every document of the fixture set
+//
+// Subjects and their offerings are written first, since users' grades are filed under their offerings
+func (f *Fixture) Load(DB db.Env) error {
+	batch := DB.Client.Batch()
+
+	for _, s := range f.Subjects {
+		sub := s.model()
+		batch.Set(DB.Client.Collection("subjects").Doc(sub.Hash()), sub)
+
+		for _, o := range s.Offerings {
+			f.addOffering(DB, batch, sub, o)
+		}
+	}
+
+	for _, c := range f.Courses {
+		course := models.Course{Name: c.Name, Code: c.Code, Specialization: c.Specialization, SubjectCodes: c.Subjects}
+		batch.Set(DB.Client.Collection("courses").Doc(course.Hash()), course)
+	}
+
+	if _, err := batch.Commit(DB.Ctx); err != nil {
+		return fmt.Errorf("could not write subjects and courses: %s", err.Error())
+	}
+
+	for _, u := range f.Users {
+		if err := f.addUser(DB, u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Fixture) addOffering(DB db.Env, batch *firestore.WriteBatch, sub models.Subject, o FixtureOffering) {
+	off := models.Offering{CodPes: o.Code, Professor: o.Professor, Years: o.Years}
+	offPath := fmt.Sprintf("subjects/%s/offerings/%s", sub.Hash(), off.Hash())
+
+	for _, c := range o.Comments {
+		comment := models.Comment{
+			ID:        uuid.New(),
+			Rating:    c.Rating,
+			Body:      c.Body,
+			Timestamp: time.Now(),
+		}
+
+		for _, v := range c.Votes {
+			if v.Upvote {
+				comment.Upvotes++
+			} else {
+				comment.Downvotes++
+			}
+
+			batch.Set(DB.Client.Doc(fmt.Sprintf("users/%s/comment_ratings/%s", utils.SHA256(v.User), comment.ID)), models.CommentRating{
+				ID:             comment.ID,
+				Upvote:         v.Upvote,
+				ProfessorHash:  off.Hash(),
+				Subject:        sub.Code,
+				Course:         sub.CourseCode,
+				Specialization: sub.Specialization,
+			})
+		}
+
+		off.Stats.Add(c.Rating)
+		authorHash := utils.SHA256(c.User)
+		batch.Set(DB.Client.Doc(offPath+"/comments/"+authorHash), comment)
+
+		replica := models.UserComment{
+			Comment:        comment,
+			ProfessorHash:  off.Hash(),
+			Subject:        sub.Code,
+			Course:         sub.CourseCode,
+			Specialization: sub.Specialization,
+		}
+		batch.Set(DB.Client.Doc(fmt.Sprintf("users/%s/user_comments/%s", authorHash, replica.Hash())), replica)
+	}
+
+	// offerings are written whole, Offering.Insert would leave the stats out
+	batch.Set(DB.Client.Doc(offPath), off)
+}
+
+func (f *Fixture) addUser(DB db.Env, u FixtureUser) error {
+	user, err := models.NewUser(u.ID, u.Name, u.Email, u.Password, time.Now())
+	if err != nil {
+		return fmt.Errorf("could not create user %s: %s", u.ID, err.Error())
+	}
+
+	user.Verified = u.Verified
+	user.Banned = u.Banned
+
+	transcript := iddigital.Transcript{
+		Name:           u.Name,
+		Nusp:           u.ID,
+		Course:         u.Course,
+		Specialization: u.Specialization,
+	}
+
+	for _, r := range u.Transcript {
+		transcript.Grades = append(transcript.Grades, r.model())
+	}
+
+	if err := account.InsertUser(DB, user, &transcript); err != nil {
+		return fmt.Errorf("could not insert user %s: %s", u.ID, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package emulator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	entries, err := fixtureFiles.ReadDir("fixtures")
+	require.NoError(t, err)
+
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".yaml")
+		fixture, err := LoadFixture(name)
+		if assert.NoError(t, err, name) {
+			assert.NotEmpty(t, fixture.Subjects, name)
+		}
+	}
+
+	_, err = LoadFixture("../default")
+	assert.Error(t, err, "fixture names cannot be paths")
+}
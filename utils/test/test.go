@@ -15,9 +15,9 @@ import (
 )
 
 // setupAccessToken fetches the jwt token used for private and restricted tests
-func setupAccessToken(router *gin.Engine) (*http.Cookie, error) {
+func setupAccessToken(router *gin.Engine, login, pwd string) (*http.Cookie, error) {
 	// Login data
-	jsonBody := map[string]interface{}{"login": "123456789", "pwd": "r4nd0mpass123!@#", "remember": true}
+	jsonBody := map[string]interface{}{"login": login, "pwd": pwd, "remember": true}
 	payloadBuf := new(bytes.Buffer)
 	json.NewEncoder(payloadBuf).Encode(jsonBody)
 
@@ -35,17 +35,31 @@ func setupAccessToken(router *gin.Engine) (*http.Cookie, error) {
 // GetEnvironment will reinitialize the testing environment.
 // It requires a suite because it is meant to be run with suites, so it can fail their test context in case of errors
 func MustGetEnvironment(s suite.Suite) (DB db.Env, router *gin.Engine, cookie *http.Cookie) {
-	DB = emulator.MustGet()
+	return MustGetFixtureEnvironment(s, emulator.DefaultFixture)
+}
+
+// MustGetFixtureEnvironment is like MustGetEnvironment, but loads the fixture set with the given name.
+// The access token belongs to the first user of the fixture set, it is nil if the set has no users
+func MustGetFixtureEnvironment(s suite.Suite, fixture string) (DB db.Env, router *gin.Engine, cookie *http.Cookie) {
+	set, err := emulator.LoadFixture(fixture)
+	if err != nil {
+		s.T().Fatal(err)
+	}
+
+	DB = emulator.MustGetFixture(fixture)
 
 	// setup router
-	var err error
 	router, err = server.SetupRouter(DB)
 	if err != nil {
 		s.T().Fatal(err)
 	}
 
+	if len(set.Users) == 0 {
+		return
+	}
+
 	// get valid AccessToken
-	if cookie, err = setupAccessToken(router); err != nil {
+	if cookie, err = setupAccessToken(router, set.Users[0].ID, set.Users[0].Password); err != nil {
 		s.T().Fatal(err)
 	}
 